@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+	"slices"
+)
+
+type AccessibilityRole string
+
+const (
+	AccessibilityRoleNone      AccessibilityRole = ""
+	AccessibilityRoleWindow    AccessibilityRole = "window"
+	AccessibilityRoleGroup     AccessibilityRole = "group"
+	AccessibilityRoleButton    AccessibilityRole = "button"
+	AccessibilityRoleSwitch    AccessibilityRole = "switch"
+	AccessibilityRoleText      AccessibilityRole = "text"
+	AccessibilityRoleTextField AccessibilityRole = "textfield"
+	AccessibilityRoleImage     AccessibilityRole = "image"
+	AccessibilityRoleList      AccessibilityRole = "list"
+	AccessibilityRoleListItem  AccessibilityRole = "listitem"
+	AccessibilityRoleMenu      AccessibilityRole = "menu"
+	AccessibilityRoleMenuItem  AccessibilityRole = "menuitem"
+	AccessibilityRoleMenuBar   AccessibilityRole = "menubar"
+	AccessibilityRoleComboBox  AccessibilityRole = "combobox"
+	AccessibilityRoleSeparator AccessibilityRole = "separator"
+)
+
+type AccessibilityAction string
+
+const (
+	AccessibilityActionPress  AccessibilityAction = "press"
+	AccessibilityActionFocus  AccessibilityAction = "focus"
+	AccessibilityActionSelect AccessibilityAction = "select"
+)
+
+// Accessibility represents the semantics of a widget for assistive technologies and test tools.
+type Accessibility struct {
+	Role  AccessibilityRole `json:"role"`
+	Label string            `json:"label,omitempty"`
+	Value string            `json:"value,omitempty"`
+
+	Checked   bool `json:"checked,omitempty"`
+	Selected  bool `json:"selected,omitempty"`
+	Expanded  bool `json:"expanded,omitempty"`
+	ReadOnly  bool `json:"readOnly,omitempty"`
+	Multiline bool `json:"multiline,omitempty"`
+
+	// Disabled reports whether the widget is disabled in addition to the widget's enabled state.
+	Disabled bool `json:"disabled,omitempty"`
+
+	Actions []AccessibilityAction `json:"actions,omitempty"`
+
+	// Leaf reports whether the descendants of the widget are excluded from the accessibility tree.
+	// Leaf is useful when the widget is composed of other accessible widgets only for its presentation.
+	Leaf bool `json:"-"`
+}
+
+// Accessible is an optional interface for a widget to expose its semantics.
+type Accessible interface {
+	Accessibility(context *Context) Accessibility
+
+	// PerformAccessibilityAction performs the action and reports whether the action is performed.
+	PerformAccessibilityAction(context *Context, action AccessibilityAction) bool
+}
+
+// AccessibilityNode is a node of an accessibility tree snapshot.
+type AccessibilityNode struct {
+	Accessibility
+
+	// ID identifies the node in the latest snapshot. See Context.PerformAccessibilityAction.
+	ID int `json:"id"`
+
+	Bounds  image.Rectangle `json:"bounds"`
+	Focused bool            `json:"focused,omitempty"`
+
+	Children []AccessibilityNode `json:"children,omitempty"`
+}
+
+// AccessibilitySnapshot walks the widget tree built at the last layout pass and returns its accessibility tree.
+//
+// Only visible widgets implementing Accessible are included. The children of a widget not implementing Accessible
+// are hoisted to the nearest accessible ancestor. The root node always represents the root widget.
+func (c *Context) AccessibilitySnapshot() AccessibilityNode {
+	a := c.app
+	a.accessibilityWidgets = slices.Delete(a.accessibilityWidgets, 0, len(a.accessibilityWidgets))
+
+	node := a.accessibilityNode(a.root)
+	if node.Role == AccessibilityRoleNone {
+		node.Role = AccessibilityRoleWindow
+	}
+	if !node.Leaf {
+		node.Children = a.appendAccessibilityNodes(nil, a.root)
+	}
+	return node
+}
+
+// PerformAccessibilityAction performs the action on the widget of the node with the given ID in the latest snapshot.
+//
+// PerformAccessibilityAction reports whether the action is performed.
+// If the widget doesn't handle AccessibilityActionFocus, the widget is focused by Focus.
+func (c *Context) PerformAccessibilityAction(id int, action AccessibilityAction) bool {
+	a := c.app
+	if id < 1 || id > len(a.accessibilityWidgets) {
+		return false
+	}
+	widget := a.accessibilityWidgets[id-1]
+	if !widget.widgetState().isInTree() || !IsVisible(widget) || !IsEnabled(widget) {
+		return false
+	}
+	if accessible, ok := widget.(Accessible); ok {
+		if accessible.PerformAccessibilityAction(c, action) {
+			return true
+		}
+	}
+	if action == AccessibilityActionFocus {
+		Focus(widget)
+		return IsFocused(widget) || HasFocusedChildWidget(widget)
+	}
+	return false
+}
+
+func (a *app) accessibilityNode(widget Widget) AccessibilityNode {
+	a.accessibilityWidgets = append(a.accessibilityWidgets, widget)
+	node := AccessibilityNode{
+		ID:     len(a.accessibilityWidgets),
+		Bounds: VisibleBounds(widget),
+	}
+	if accessible, ok := widget.(Accessible); ok {
+		node.Accessibility = accessible.Accessibility(&a.context)
+	}
+	// The focus on a descendant of a leaf is the focus on the leaf.
+	node.Focused = IsFocused(widget) || node.Leaf && HasFocusedChildWidget(widget)
+	if !IsEnabled(widget) {
+		node.Disabled = true
+	}
+	return node
+}
+
+func (a *app) appendAccessibilityNodes(nodes []AccessibilityNode, widget Widget) []AccessibilityNode {
+	for _, child := range widget.widgetState().children {
+		if !IsVisible(child) {
+			continue
+		}
+		if _, ok := child.(Accessible); !ok {
+			nodes = a.appendAccessibilityNodes(nodes, child)
+			continue
+		}
+		node := a.accessibilityNode(child)
+		if !node.Leaf {
+			node.Children = a.appendAccessibilityNodes(nil, child)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
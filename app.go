@@ -4,11 +4,11 @@
 package guigui
 
 import (
+	"cmp"
 	"fmt"
 	"image"
 	"image/color"
 	"log/slog"
-	"maps"
 	"math"
 	"os"
 	"slices"
@@ -43,15 +43,29 @@ func invalidatedRegionForDebugMaxTime() int {
 	return ebiten.TPS() / 5
 }
 
-var theApp *app
-
 type app struct {
-	root      Widget
-	context   Context
-	visitedZs map[int]struct{}
-	zs        []int
+	root    Widget
+	context Context
+
+	// widgets is the widgets in the tree in the pre-order, updated when the tree might be changed at a layout pass.
+	// zOrder is the indices of widgets stably sorted by z values.
+	// Widgets are drawn in zOrder, and handle inputs in the reverse order.
+	widgets []Widget
+	zOrder  []int
+
+	// skipped and visibleBounds are the states of widgets for a pass.
+	skipped       []bool
+	visibleBounds []image.Rectangle
+	opacityLayers []opacityLayer
+
+	// frame is incremented every update. Cached measurements are valid only in the same frame.
+	frame int64
 
-	invalidatedRegions image.Rectangle
+	// fullRelayoutRequested is true when all the widgets must be laid out at the next layout pass.
+	fullRelayoutRequested bool
+	lastLayoutBounds      image.Rectangle
+
+	invalidatedRegions regionSet
 	invalidatedWidgets []Widget
 
 	invalidatedRegionsForDebug []invalidatedRegionsForDebugItem
@@ -63,7 +77,16 @@ type app struct {
 	lastScreenHeight float64
 	lastScale        float64
 
-	focusedWidget Widget
+	focusedWidget  Widget
+	onFocusChanged func(widget Widget)
+
+	inputSource        InputSource
+	inputState         InputState
+	currentCursorShape ebiten.CursorShapeType
+
+	accessibilityWidgets []Widget
+
+	commandRegistry commandRegistry
 
 	offscreen   *ebiten.Image
 	debugScreen *ebiten.Image
@@ -77,6 +100,7 @@ type RunOptions struct {
 	WindowMaxHeight   int
 	AppScale          float64
 	ScreenTransparent bool
+	InputSource       InputSource
 }
 
 func Run(root Widget, options *RunOptions) error {
@@ -105,12 +129,11 @@ func Run(root Widget, options *RunOptions) error {
 	}
 	ebiten.SetWindowSizeLimits(minW, minH, maxW, maxH)
 
-	a := &app{
-		root: root,
+	inputSource := options.InputSource
+	if inputSource == nil {
+		inputSource = ebitenInputSource{}
 	}
-	theApp = a
-	a.root.widgetState().root = true
-	a.context.app = a
+	a := newApp(root, inputSource)
 	if options.AppScale > 0 {
 		a.context.appScaleMinus1 = options.AppScale - 1
 	}
@@ -123,25 +146,61 @@ func Run(root Widget, options *RunOptions) error {
 	return ebiten.RunGameWithOptions(a, eop)
 }
 
+func newApp(root Widget, inputSource InputSource) *app {
+	a := &app{
+		root:        root,
+		inputSource: inputSource,
+	}
+	rootState := a.root.widgetState()
+	rootState.root = true
+	rootState.app = a
+	a.context.app = a
+	return a
+}
+
 func (a app) bounds() image.Rectangle {
 	return image.Rect(0, 0, int(math.Ceil(a.screenWidth)), int(math.Ceil(a.screenHeight)))
 }
 
 func (a *app) Update() error {
+	if err := a.update(ebiten.Monitor().DeviceScaleFactor(), a.inputSource); err != nil {
+		return err
+	}
+	ebiten.SetCursorShape(a.currentCursorShape)
+	return nil
+}
+
+func (a *app) update(deviceScale float64, inputSource InputSource) error {
+	a.frame++
+
 	rootState := a.root.widgetState()
 	rootState.position = image.Point{}
 
-	a.context.setDeviceScale(ebiten.Monitor().DeviceScaleFactor())
+	a.context.setDeviceScale(deviceScale)
+	a.inputState.update(inputSource)
 
 	// AppendChildWidgets
 	a.layout()
 
 	// HandleInput
 	// TODO: Handle this in Ebitengine's HandleInput in the future (hajimehoshi/ebiten#1704)
-	a.handleInputWidget()
+	r := a.handleInputWidget()
+
+	// Dispatch key chords to commands unless the focused widget or its ancestors handled the inputs,
+	// so that a focused widget can take precedence over commands bound to the same chords, e.g. Ctrl+Z in a text field.
+	// While a focus trap like a modal popup is visible, the keys belong to the focus trap and are not dispatched.
+	var commandExecuted bool
+	if !r.aborted && !a.isHandledByFocusedWidget(r) && a.topmostFocusTrap() == nil {
+		commandExecuted = a.handleCommands()
+	}
+
+	// Move the focus by Tab and Shift+Tab.
+	if !commandExecuted {
+		a.handleFocusNavigation()
+	}
 
 	if !a.cursorShape() {
-		ebiten.SetCursorShape(ebiten.CursorShapeDefault)
+		a.currentCursorShape = ebiten.CursorShapeDefault
 	}
 
 	// Update
@@ -161,9 +220,9 @@ func (a *app) Update() error {
 		invalidated = true
 		a.lastScreenHeight = a.screenHeight
 	}
-	if s := ebiten.Monitor().DeviceScaleFactor(); a.lastScale != s {
+	if a.lastScale != deviceScale {
 		invalidated = true
-		a.lastScale = s
+		a.lastScale = deviceScale
 	}
 	if invalidated {
 		a.requestRedraw(a.bounds())
@@ -184,7 +243,7 @@ func (a *app) Update() error {
 			if theDebugMode.showRenderingRegions {
 				slog.Info("Request redrawing", "requester", fmt.Sprintf("%T", widget), "region", vb)
 			}
-			a.invalidatedRegions = a.invalidatedRegions.Union(vb)
+			a.invalidatedRegions.add(vb)
 		}
 		a.invalidatedWidgets = slices.Delete(a.invalidatedWidgets, 0, len(a.invalidatedWidgets))
 	}
@@ -199,13 +258,13 @@ func (a *app) Update() error {
 			}
 		}
 
-		if !a.invalidatedRegions.Empty() {
+		for _, region := range a.invalidatedRegions.rects {
 			idx := slices.IndexFunc(a.invalidatedRegionsForDebug, func(i invalidatedRegionsForDebugItem) bool {
-				return i.region.Eq(a.invalidatedRegions)
+				return i.region.Eq(region)
 			})
 			if idx < 0 {
 				a.invalidatedRegionsForDebug = append(a.invalidatedRegionsForDebug, invalidatedRegionsForDebugItem{
-					region: a.invalidatedRegions,
+					region: region,
 					time:   invalidatedRegionForDebugMaxTime(),
 				})
 			} else {
@@ -218,6 +277,10 @@ func (a *app) Update() error {
 }
 
 func (a *app) Draw(screen *ebiten.Image) {
+	a.draw(screen)
+}
+
+func (a *app) draw(screen *ebiten.Image) {
 	origScreen := screen
 	if theDebugMode.showRenderingRegions {
 		if a.offscreen != nil {
@@ -233,7 +296,11 @@ func (a *app) Draw(screen *ebiten.Image) {
 	}
 	a.drawWidget(screen)
 	a.drawDebugIfNeeded(origScreen)
-	a.invalidatedRegions = image.Rectangle{}
+	a.resetInvalidatedRegions()
+}
+
+func (a *app) resetInvalidatedRegions() {
+	a.invalidatedRegions.reset()
 	a.invalidatedWidgets = slices.Delete(a.invalidatedWidgets, 0, len(a.invalidatedWidgets))
 }
 
@@ -242,20 +309,23 @@ func (a *app) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func (a *app) LayoutF(outsideWidth, outsideHeight float64) (float64, float64) {
-	s := ebiten.Monitor().DeviceScaleFactor()
-	a.screenWidth = outsideWidth * s
-	a.screenHeight = outsideHeight * s
+	a.setOutsideSize(outsideWidth, outsideHeight, ebiten.Monitor().DeviceScaleFactor())
 	return a.screenWidth, a.screenHeight
 }
 
+func (a *app) setOutsideSize(outsideWidth, outsideHeight float64, deviceScale float64) {
+	a.screenWidth = outsideWidth * deviceScale
+	a.screenHeight = outsideHeight * deviceScale
+}
+
 func (a *app) requestRedraw(region image.Rectangle) {
-	a.invalidatedRegions = a.invalidatedRegions.Union(region)
+	a.invalidatedRegions.add(region)
 }
 
 func (a *app) requestRedrawWidget(widget Widget) {
 	a.invalidatedWidgets = append(a.invalidatedWidgets, widget)
 	for _, child := range widget.widgetState().children {
-		theApp.requestRedrawIfAboveParentZ(child)
+		a.requestRedrawIfAboveParentZ(child)
 	}
 }
 
@@ -269,111 +339,162 @@ func (a *app) requestRedrawIfAboveParentZ(widget Widget) {
 	}
 }
 
-func (a *app) layout() {
-	a.doLayout(a.root)
-
-	// Calculate z values.
-	clear(a.visitedZs)
-	traverseWidget(a.root, func(widget Widget) {
-		if a.visitedZs == nil {
-			a.visitedZs = map[int]struct{}{}
-		}
-		a.visitedZs[z(widget)] = struct{}{}
-	})
+func (a *app) requestFullRelayout() {
+	a.fullRelayoutRequested = true
+}
 
-	a.zs = slices.Delete(a.zs, 0, len(a.zs))
-	a.zs = slices.AppendSeq(a.zs, maps.Keys(a.visitedZs))
-	slices.Sort(a.zs)
+func (a *app) layout() {
+	// An input can change any state of the app via callbacks, so lay out all the widgets.
+	// Without inputs, only the widgets requesting relayout and the widgets whose bounds are changed are laid out.
+	if a.inputState.hasEvents() {
+		a.fullRelayoutRequested = true
+	}
+	if b := a.bounds(); a.lastLayoutBounds != b {
+		a.fullRelayoutRequested = true
+		a.lastLayoutBounds = b
+	}
+	full := a.fullRelayoutRequested
+	a.fullRelayoutRequested = false
+	if a.doLayout(a.root, full) || len(a.widgets) == 0 {
+		a.flattenWidgets()
+	}
 }
 
-func (a *app) doLayout(widget Widget) {
+// doLayout lays out the widget and its descendants if needed.
+// doLayout reports whether Layout is called for any widget.
+func (a *app) doLayout(widget Widget, full bool) bool {
 	widgetState := widget.widgetState()
+	bounds := Bounds(widget)
+
+	// Skip the subtree if the widget is not changed.
+	// A request to relayout a descendant is also marked on the widget, so the descendants are not changed either.
+	if !full && !widgetState.needsLayout && widgetState.laidOut && widgetState.layoutBounds == bounds {
+		return false
+	}
+
+	// The descendants might depend on the widget's state, so lay out the whole subtree when the widget itself requests relayout.
+	subtree := full || widgetState.relayoutRequested
+
+	// Reset the flags before Layout, as Layout might request relayout of the widget again for the next pass.
+	widgetState.needsLayout = false
+	widgetState.relayoutRequested = false
+	widgetState.laidOut = true
+	widgetState.layoutBounds = bounds
+
 	widgetState.children = slices.Delete(widgetState.children, 0, len(widgetState.children))
 	widget.Layout(&a.context, &ChildWidgetAppender{
 		app:    a,
 		widget: widget,
 	})
 	for _, child := range widgetState.children {
-		a.doLayout(child)
+		a.doLayout(child, subtree)
 	}
+	return true
 }
 
-func (a *app) handleInputWidget() HandleInputResult {
-	for i := len(a.zs) - 1; i >= 0; i-- {
-		z := a.zs[i]
-		if r := a.doHandleInputWidget(a.root, z); r.ShouldRaise() {
-			return r
-		}
-	}
-	return HandleInputResult{}
-}
+// flattenWidgets calculates the z values and the traversal orders of the widgets.
+func (a *app) flattenWidgets() {
+	a.widgets = slices.Delete(a.widgets, 0, len(a.widgets))
+	a.appendWidgets(a.root, 0)
 
-func (a *app) doHandleInputWidget(widget Widget, zToHandle int) HandleInputResult {
-	if zToHandle < z(widget) {
-		return HandleInputResult{}
+	a.zOrder = slices.Delete(a.zOrder, 0, len(a.zOrder))
+	for i := range a.widgets {
+		a.zOrder = append(a.zOrder, i)
 	}
+	slices.SortStableFunc(a.zOrder, func(i, j int) int {
+		return cmp.Compare(a.widgets[i].widgetState().z, a.widgets[j].widgetState().z)
+	})
+}
 
+func (a *app) appendWidgets(widget Widget, parentZ int) {
 	widgetState := widget.widgetState()
-	if widgetState.hidden {
-		return HandleInputResult{}
-	}
-
-	// Iterate the children in the reverse order of rendering.
-	for i := len(widgetState.children) - 1; i >= 0; i-- {
-		child := widgetState.children[i]
-		if r := a.doHandleInputWidget(child, zToHandle); r.ShouldRaise() {
-			return r
-		}
+	widgetState.z = parentZ
+	if widget.IsPopup() {
+		widgetState.z++
 	}
-
-	if zToHandle != z(widget) {
-		return HandleInputResult{}
+	widgetState.treeIndex = len(a.widgets)
+	a.widgets = append(a.widgets, widget)
+	for _, child := range widgetState.children {
+		a.appendWidgets(child, widgetState.z)
 	}
-	return widget.HandleInput(&a.context)
+	widgetState.treeEnd = len(a.widgets)
 }
 
-func (a *app) cursorShape() bool {
-	for i := len(a.zs) - 1; i >= 0; i-- {
-		z := a.zs[i]
-		if a.doCursorShape(a.root, z) {
-			return true
+// updateSkippedWidgets updates the widgets to skip in a pass.
+// A widget is skipped when skip returns true for the widget or its ancestors.
+// If withBounds is true, the visible bounds are also updated and passed to skip.
+func (a *app) updateSkippedWidgets(withBounds bool, skip func(widget Widget, visibleBounds image.Rectangle) bool) {
+	a.skipped = slices.Grow(a.skipped[:0], len(a.widgets))[:len(a.widgets)]
+	if withBounds {
+		a.visibleBounds = slices.Grow(a.visibleBounds[:0], len(a.widgets))[:len(a.widgets)]
+	}
+	for i, widget := range a.widgets {
+		widgetState := widget.widgetState()
+		parentIndex := -1
+		if i > 0 {
+			parentIndex = widgetState.parent.widgetState().treeIndex
 		}
-	}
-	return false
-}
 
-func (a *app) doCursorShape(widget Widget, zToHandle int) bool {
-	if zToHandle < z(widget) {
-		return false
-	}
+		var vb image.Rectangle
+		if withBounds {
+			// This is the same as VisibleBounds.
+			switch {
+			case parentIndex < 0:
+				vb = a.bounds()
+			case isAboveParentZ(widget):
+				vb = Bounds(widget)
+			default:
+				vb = a.visibleBounds[parentIndex].Intersect(Bounds(widget))
+			}
+			a.visibleBounds[i] = vb
+		}
 
-	widgetState := widget.widgetState()
-	if widgetState.hidden {
-		return false
+		a.skipped[i] = parentIndex >= 0 && a.skipped[parentIndex] || skip(widget, vb)
 	}
+}
 
-	// Iterate the children in the reverse order of rendering.
-	for i := len(widgetState.children) - 1; i >= 0; i-- {
-		child := widgetState.children[i]
-		if a.doCursorShape(child, zToHandle) {
-			return true
-		}
-	}
+func (a *app) handleInputWidget() HandleInputResult {
+	a.updateSkippedWidgets(false, func(widget Widget, visibleBounds image.Rectangle) bool {
+		return widget.widgetState().hidden
+	})
 
-	if zToHandle != z(widget) {
-		return false
+	// Iterate the widgets in the reverse order of rendering.
+	for i := len(a.zOrder) - 1; i >= 0; i-- {
+		idx := a.zOrder[i]
+		widget := a.widgets[idx]
+		// A widget might be hidden by another widget's HandleInput.
+		if a.skipped[idx] || widget.widgetState().hidden {
+			continue
+		}
+		if r := widget.HandleInput(&a.context); r.ShouldRaise() {
+			return r
+		}
 	}
+	return HandleInputResult{}
+}
 
-	if !image.Pt(ebiten.CursorPosition()).In(VisibleBounds(widget)) {
-		return false
-	}
+func (a *app) cursorShape() bool {
+	a.updateSkippedWidgets(true, func(widget Widget, visibleBounds image.Rectangle) bool {
+		return widget.widgetState().hidden
+	})
 
-	shape, ok := widget.CursorShape(&a.context)
-	if !ok {
-		return false
+	// Iterate the widgets in the reverse order of rendering.
+	for i := len(a.zOrder) - 1; i >= 0; i-- {
+		idx := a.zOrder[i]
+		if a.skipped[idx] {
+			continue
+		}
+		if !a.inputState.CursorPosition().In(a.visibleBounds[idx]) {
+			continue
+		}
+		shape, ok := a.widgets[idx].CursorShape(&a.context)
+		if !ok {
+			continue
+		}
+		a.currentCursorShape = shape
+		return true
 	}
-	ebiten.SetCursorShape(shape)
-	return true
+	return false
 }
 
 func (a *app) updateWidget(widget Widget) error {
@@ -403,7 +524,7 @@ func (a *app) requestRedrawIfTreeChanged(widget Widget) {
 	// If the children and/or children's bounds are changed, request redraw.
 	if !widgetState.prev.equals(widgetState.children) {
 		// Widgets above their parents' Z (e.g. popups) are outside of widget, so redraw the regions explicitly.
-		widgetState.prev.redrawIfAboveParentZ()
+		widgetState.prev.redrawIfAboveParentZ(a)
 		a.requestRedraw(VisibleBounds(widget))
 		for _, child := range widgetState.children {
 			if isAboveParentZ(child) {
@@ -428,57 +549,71 @@ func (a *app) resetPrevWidgets(widget Widget) {
 	}
 }
 
-func (a *app) drawWidget(screen *ebiten.Image) {
-	if a.invalidatedRegions.Empty() {
-		return
-	}
-	dst := screen.SubImage(a.invalidatedRegions).(*ebiten.Image)
-	for _, z := range a.zs {
-		a.doDrawWidget(dst, a.root, z)
-	}
+// opacityLayer is a translucent widget being rendered to its offscreen.
+type opacityLayer struct {
+	widget Widget
+	dst    *ebiten.Image
 }
 
-func (a *app) doDrawWidget(dst *ebiten.Image, widget Widget, zToRender int) {
-	if zToRender < z(widget) {
+func (a *app) drawWidget(screen *ebiten.Image) {
+	if a.invalidatedRegions.empty() {
 		return
 	}
 
-	vb := VisibleBounds(widget)
-	if vb.Empty() {
-		return
-	}
+	a.updateSkippedWidgets(true, func(widget Widget, visibleBounds image.Rectangle) bool {
+		widgetState := widget.widgetState()
+		return visibleBounds.Empty() || widgetState.hidden || widgetState.opacity() == 0
+	})
 
-	widgetState := widget.widgetState()
-	if widgetState.hidden {
-		return
-	}
-	if widgetState.opacity() == 0 {
-		return
+	// The regions don't overlap, so each region can be drawn separately.
+	for _, region := range a.invalidatedRegions.rects {
+		a.drawWidgetsInRegion(screen.SubImage(region).(*ebiten.Image))
 	}
+}
+
+func (a *app) drawWidgetsInRegion(dst *ebiten.Image) {
+	region := dst.Bounds()
+	for _, idx := range a.zOrder {
+		widget := a.widgets[idx]
+		widgetState := widget.widgetState()
+
+		// Render the translucent widgets whose subtrees in the same z are finished.
+		for len(a.opacityLayers) > 0 {
+			l := a.opacityLayers[len(a.opacityLayers)-1]
+			if lState := l.widget.widgetState(); lState.z == widgetState.z && idx < lState.treeEnd {
+				break
+			}
+			dst = a.popOpacityLayer(dst)
+		}
 
-	var origDst *ebiten.Image
-	renderCurrent := zToRender == z(widget)
-	if renderCurrent {
+		if a.skipped[idx] || !a.visibleBounds[idx].Overlaps(region) {
+			continue
+		}
 		if widgetState.opacity() < 1 {
-			origDst = dst
+			a.opacityLayers = append(a.opacityLayers, opacityLayer{
+				widget: widget,
+				dst:    dst,
+			})
 			dst = widgetState.ensureOffscreen(dst.Bounds())
 			dst.Clear()
 		}
-		widget.Draw(&a.context, dst.SubImage(vb).(*ebiten.Image))
+		widget.Draw(&a.context, dst.SubImage(a.visibleBounds[idx]).(*ebiten.Image))
 	}
-
-	for _, child := range widgetState.children {
-		a.doDrawWidget(dst, child, zToRender)
+	for len(a.opacityLayers) > 0 {
+		dst = a.popOpacityLayer(dst)
 	}
+}
 
-	if renderCurrent {
-		if widgetState.opacity() < 1 {
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(dst.Bounds().Min.X), float64(dst.Bounds().Min.Y))
-			op.ColorScale.ScaleAlpha(float32(widgetState.opacity()))
-			origDst.DrawImage(dst, op)
-		}
-	}
+// popOpacityLayer renders the offscreen of the last translucent widget, and returns the destination image to render the next widgets.
+func (a *app) popOpacityLayer(offscreen *ebiten.Image) *ebiten.Image {
+	l := a.opacityLayers[len(a.opacityLayers)-1]
+	a.opacityLayers = slices.Delete(a.opacityLayers, len(a.opacityLayers)-1, len(a.opacityLayers))
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(offscreen.Bounds().Min.X), float64(offscreen.Bounds().Min.Y))
+	op.ColorScale.ScaleAlpha(float32(l.widget.widgetState().opacity()))
+	l.dst.DrawImage(offscreen, op)
+	return l.dst
 }
 
 func (a *app) drawDebugIfNeeded(screen *ebiten.Image) {
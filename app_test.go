@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui_test
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+type benchWidget struct {
+	guigui.DefaultWidget
+
+	children []*benchWidget
+	popup    bool
+}
+
+func (b *benchWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for _, child := range b.children {
+		guigui.SetPosition(child, guigui.Position(b).Add(image.Pt(1, 1)))
+		appender.AppendChildWidget(child)
+	}
+}
+
+func (b *benchWidget) IsPopup() bool {
+	return b.popup
+}
+
+func (b *benchWidget) Size(context *guigui.Context) (int, int) {
+	return 100, 100
+}
+
+type benchRoot struct {
+	guigui.RootWidget
+
+	child *benchWidget
+}
+
+func (b *benchRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	appender.AppendChildWidget(b.child)
+}
+
+// newDeepTree returns a tree of a chain of widgets. Every popupInterval-th widget is a popup if popupInterval is positive.
+func newDeepTree(depth int, popupInterval int) *benchWidget {
+	root := &benchWidget{}
+	w := root
+	for i := 1; i < depth; i++ {
+		child := &benchWidget{
+			popup: popupInterval > 0 && i%popupInterval == 0,
+		}
+		w.children = append(w.children, child)
+		w = child
+	}
+	return root
+}
+
+// newWideTree returns a tree of a widget with many children.
+func newWideTree(width int) *benchWidget {
+	root := &benchWidget{}
+	for range width {
+		root.children = append(root.children, &benchWidget{})
+	}
+	return root
+}
+
+type benchInputSource struct{}
+
+func (benchInputSource) CursorPosition() (int, int) {
+	return 50, 50
+}
+
+func (benchInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return false
+}
+
+func (benchInputSource) Wheel() (float64, float64) {
+	return 0, 0
+}
+
+func (benchInputSource) IsKeyPressed(key ebiten.Key) bool {
+	return false
+}
+
+func (benchInputSource) AppendInputChars(runes []rune) []rune {
+	return runes
+}
+
+func (benchInputSource) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return touches
+}
+
+func (benchInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	return 0, 0
+}
+
+func benchmarkUpdate(b *testing.B, tree *benchWidget) {
+	h := guigui.NewHeadless(&benchRoot{child: tree}, &guigui.HeadlessOptions{
+		Width:       640,
+		Height:      480,
+		InputSource: benchInputSource{},
+	})
+	if err := h.Update(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if err := h.Update(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUpdateDeepTree(b *testing.B) {
+	benchmarkUpdate(b, newDeepTree(1000, 0))
+}
+
+func BenchmarkUpdateDeepTreeWithPopups(b *testing.B) {
+	benchmarkUpdate(b, newDeepTree(1000, 100))
+}
+
+func BenchmarkUpdateWideTree(b *testing.B) {
+	benchmarkUpdate(b, newWideTree(5000))
+}
+
+type layoutWidget struct {
+	guigui.DefaultWidget
+
+	parent   *layoutWidget
+	children []*layoutWidget
+	height   int
+
+	// state is read by the children at Layout.
+	state int
+
+	layoutCount int
+	parentState int
+}
+
+func (l *layoutWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	l.layoutCount++
+	if l.parent != nil {
+		l.parentState = l.parent.state
+	}
+	// Stack the children vertically.
+	p := guigui.Position(l)
+	for _, child := range l.children {
+		guigui.SetPosition(child, p)
+		appender.AppendChildWidget(child)
+		_, h := child.Size(context)
+		p.Y += h
+	}
+}
+
+func (l *layoutWidget) Size(context *guigui.Context) (int, int) {
+	return 100, l.height
+}
+
+func (l *layoutWidget) setHeight(height int) {
+	if l.height == height {
+		return
+	}
+	l.height = height
+	guigui.RequestRelayout(l)
+}
+
+func (l *layoutWidget) addChild() *layoutWidget {
+	child := &layoutWidget{
+		parent: l,
+		height: 10,
+	}
+	l.children = append(l.children, child)
+	return child
+}
+
+type layoutRoot struct {
+	guigui.RootWidget
+
+	child *layoutWidget
+}
+
+func (l *layoutRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	appender.AppendChildWidget(l.child)
+}
+
+// newLayoutTree returns a tree of a widget with two children, each of which has a child.
+func newLayoutTree() (tree, child0, grandchild0, child1, grandchild1 *layoutWidget) {
+	tree = &layoutWidget{height: 100}
+	child0 = tree.addChild()
+	grandchild0 = child0.addChild()
+	child1 = tree.addChild()
+	grandchild1 = child1.addChild()
+	return
+}
+
+func layoutCounts(widgets ...*layoutWidget) []int {
+	counts := make([]int, len(widgets))
+	for i, w := range widgets {
+		counts[i] = w.layoutCount
+	}
+	return counts
+}
+
+func TestRelayout(t *testing.T) {
+	testCases := []struct {
+		name string
+		// change changes the tree, and returns the widgets that must be laid out again.
+		change func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget
+	}{
+		{
+			name: "none",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				return nil
+			},
+		},
+		{
+			name: "request",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				guigui.RequestRelayout(grandchild1)
+				return []*layoutWidget{tree, child1, grandchild1}
+			},
+		},
+		{
+			name: "redraw",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				// Redrawing doesn't lay out the widget again.
+				guigui.RequestRedraw(grandchild1)
+				return nil
+			},
+		},
+		{
+			name: "hide",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				guigui.Hide(grandchild0)
+				return []*layoutWidget{tree, child0, grandchild0}
+			},
+		},
+		{
+			name: "bounds",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				// Growing child0 moves child1 and its child.
+				child0.setHeight(20)
+				return []*layoutWidget{tree, child0, grandchild0, child1, grandchild1}
+			},
+		},
+		{
+			name: "ancestor state",
+			change: func(tree, child0, grandchild0, child1, grandchild1 *layoutWidget) []*layoutWidget {
+				child1.state++
+				guigui.RequestRelayout(child1)
+				return []*layoutWidget{tree, child1, grandchild1}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, child0, grandchild0, child1, grandchild1 := newLayoutTree()
+			h := guigui.NewHeadless(&layoutRoot{child: tree}, &guigui.HeadlessOptions{
+				Width:       640,
+				Height:      480,
+				InputSource: benchInputSource{},
+			})
+			for range 3 {
+				if err := h.Update(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			widgets := []*layoutWidget{tree, child0, grandchild0, child1, grandchild1}
+			before := layoutCounts(widgets...)
+			relaidOut := tc.change(tree, child0, grandchild0, child1, grandchild1)
+			if err := h.Update(); err != nil {
+				t.Fatal(err)
+			}
+			after := layoutCounts(widgets...)
+			for i, w := range widgets {
+				want := before[i]
+				if slices.Contains(relaidOut, w) {
+					want++
+				}
+				if got := after[i]; got != want {
+					t.Errorf("Layout count of widgets[%d]: got: %d, want: %d", i, got, want)
+				}
+			}
+			if got, want := grandchild1.parentState, child1.state; got != want {
+				t.Errorf("parent state at Layout: got: %d, want: %d", got, want)
+			}
+			if got, want := guigui.Position(child1).Y, guigui.Position(tree).Y+child0.height; got != want {
+				t.Errorf("child1 Y: got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
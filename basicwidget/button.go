@@ -15,6 +15,8 @@ type Button struct {
 	guigui.DefaultWidget
 
 	mouseOverlay guigui.MouseOverlay
+	tooltip      Tooltip
+	focusRing    focusRing
 
 	widthMinusDefault  int
 	heightMinusDefault int
@@ -24,6 +26,11 @@ type Button struct {
 	onUp   func()
 }
 
+// SetTooltip sets the text shown when the cursor stays on the button.
+func (b *Button) SetTooltip(text string) {
+	b.tooltip.SetText(text)
+}
+
 func (b *Button) SetOnDown(f func()) {
 	b.onDown = f
 }
@@ -52,6 +59,23 @@ func (b *Button) Layout(context *guigui.Context, appender *guigui.ChildWidgetApp
 
 	guigui.SetPosition(&b.mouseOverlay, guigui.Position(b))
 	appender.AppendChildWidget(&b.mouseOverlay)
+
+	if b.tooltip.HasContent() {
+		guigui.SetPosition(&b.tooltip, guigui.Position(b))
+		appender.AppendChildWidget(&b.tooltip)
+	}
+
+	guigui.SetFocusable(b, true)
+	appendFocusRing(context, appender, &b.focusRing, b)
+}
+
+func (b *Button) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	// A button focused by a click or keyboard navigation is pressed by Space or Enter.
+	if guigui.IsEnabled(b) && guigui.HasFocusedChildWidget(b) && isActivationKeyJustPressed(context.Input()) {
+		b.press()
+		return guigui.HandleInputByWidget(b)
+	}
+	return guigui.HandleInputResult{}
 }
 
 func (b *Button) CursorShape(context *guigui.Context) (ebiten.CursorShapeType, bool) {
@@ -101,6 +125,31 @@ func (b *Button) Draw(context *guigui.Context, dst *ebiten.Image) {
 	}
 }
 
+func (b *Button) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role:    guigui.AccessibilityRoleButton,
+		Actions: []guigui.AccessibilityAction{guigui.AccessibilityActionPress},
+		Leaf:    true,
+	}
+}
+
+func (b *Button) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress {
+		return false
+	}
+	b.press()
+	return true
+}
+
+func (b *Button) press() {
+	if b.onDown != nil {
+		b.onDown()
+	}
+	if b.onUp != nil {
+		b.onUp()
+	}
+}
+
 func (b *Button) isActive() bool {
 	return guigui.IsEnabled(b) && b.mouseOverlay.IsHovering() && b.mouseOverlay.IsPressing()
 }
@@ -111,8 +160,12 @@ func defaultButtonSize(context *guigui.Context) (int, int) {
 
 func (b *Button) SetSize(context *guigui.Context, width, height int) {
 	dw, dh := defaultButtonSize(context)
+	if b.widthMinusDefault == width-dw && b.heightMinusDefault == height-dh {
+		return
+	}
 	b.widthMinusDefault = width - dw
 	b.heightMinusDefault = height - dh
+	guigui.RequestRelayout(b)
 }
 
 func (b *Button) Size(context *guigui.Context) (int, int) {
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func TestButtonKeyboardActivation(t *testing.T) {
+	var button TextButton
+	button.SetText("OK")
+	var upCount int
+	button.SetOnUp(func() {
+		upCount++
+	})
+	var toggleButton ToggleButton
+	var numberInput NumberInput
+	d := newTestDriver(&button, &toggleButton, &numberInput)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The buttons are visited by Tab in the tree order.
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if got, want := d.FocusedWidget(), guigui.Widget(&button.button); got != want {
+		t.Fatalf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	if !slices.Contains(d.Widgets(), guigui.Widget(&button.button.focusRing)) {
+		t.Errorf("the focus ring is not shown")
+	}
+	for _, key := range []ebiten.Key{ebiten.KeySpace, ebiten.KeyEnter} {
+		pressKeys(t, d, 1, key)
+	}
+	if got, want := upCount, 2; got != want {
+		t.Errorf("upCount: got: %d, want: %d", got, want)
+	}
+
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if got, want := d.FocusedWidget(), guigui.Widget(&toggleButton); got != want {
+		t.Fatalf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	pressKeys(t, d, 1, ebiten.KeySpace)
+	if !toggleButton.Value() {
+		t.Errorf("Value(): got: false, want: true")
+	}
+
+	// The text field and then the up and down buttons of the number input.
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if got, want := d.FocusedWidget(), guigui.Widget(&numberInput.upButton); got != want {
+		t.Fatalf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	pressKeys(t, d, 1, ebiten.KeyEnter)
+	if got, want := numberInput.Value(), 1.0; got != want {
+		t.Errorf("Value(): got: %v, want: %v", got, want)
+	}
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	pressKeys(t, d, 1, ebiten.KeySpace)
+	pressKeys(t, d, 1, ebiten.KeySpace)
+	if got, want := numberInput.Value(), -1.0; got != want {
+		t.Errorf("Value(): got: %v, want: %v", got, want)
+	}
+}
+
+func TestListKeyboardSelection(t *testing.T) {
+	var list List
+	var texts [3]Text
+	items := make([]ListItem, len(texts))
+	for i := range texts {
+		texts[i].SetText("Item")
+		items[i] = ListItem{
+			Content:    &texts[i],
+			Selectable: i != 1,
+		}
+	}
+	list.SetItems(items)
+	d := newTestDriver(&list)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if got, want := d.FocusedWidget(), guigui.Widget(&list); got != want {
+		t.Fatalf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	pressKeys(t, d, 1, ebiten.KeyEnter)
+	if got, want := list.SelectedItemIndex(), 0; got != want {
+		t.Errorf("SelectedItemIndex(): got: %d, want: %d", got, want)
+	}
+	// The unselectable item is skipped.
+	pressKeys(t, d, 2, ebiten.KeyDown)
+	if got, want := list.SelectedItemIndex(), 2; got != want {
+		t.Errorf("SelectedItemIndex(): got: %d, want: %d", got, want)
+	}
+	pressKeys(t, d, 2, ebiten.KeyUp)
+	if got, want := list.SelectedItemIndex(), 0; got != want {
+		t.Errorf("SelectedItemIndex(): got: %d, want: %d", got, want)
+	}
+}
+
+func TestPopupTrapsFocus(t *testing.T) {
+	var button Button
+	var popup Popup
+	d := newTestDriver(&button, &popup)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if got, want := d.FocusedWidget(), guigui.Widget(&button); got != want {
+		t.Fatalf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+
+	// The focus doesn't go back to the button behind the popup even though the popup has no focusable widgets.
+	popup.Open()
+	for range 2 {
+		pressKeys(t, d, 1, ebiten.KeyTab)
+		if got, want := d.FocusedWidget(), guigui.Widget(&popup); got != want {
+			t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+		}
+	}
+}
+
+func TestListContextMenuOpensAtFirstKey(t *testing.T) {
+	var list List
+	var text Text
+	text.SetText("Item")
+	list.SetItems([]ListItem{{Content: &text, Selectable: true}})
+	list.SetOnContextMenu(func(index int, position image.Point) []ContextMenuItem {
+		return []ContextMenuItem{{Text: "Delete"}}
+	})
+	d := newTestDriver(&list)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	pressKeys(t, d, 1, ebiten.KeyShift, ebiten.KeyF10)
+	if list.contextMenu == nil || !guigui.IsVisible(&list.contextMenu.popupMenu.popup) {
+		t.Errorf("the context menu is not visible after the first Shift+F10")
+	}
+}
+
+func TestWidgetAccessibilityRoles(t *testing.T) {
+	var button TextButton
+	button.SetText("OK")
+	var toggleButton ToggleButton
+	toggleButton.SetValue(true)
+	var textList TextList
+	textList.SetItemsByStrings([]string{"Foo", "Bar"})
+	textList.SetSelectedItemIndex(1)
+	var text Text
+	text.SetText("Label")
+	d := newTestDriver(&button, &toggleButton, &textList, &text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := d.Context().AccessibilitySnapshot()
+	testCases := []struct {
+		role     guigui.AccessibilityRole
+		label    string
+		checked  bool
+		selected bool
+	}{
+		{role: guigui.AccessibilityRoleButton, label: "OK"},
+		{role: guigui.AccessibilityRoleSwitch, checked: true},
+		{role: guigui.AccessibilityRoleListItem, label: "Foo"},
+		{role: guigui.AccessibilityRoleListItem, label: "Bar", selected: true},
+		{role: guigui.AccessibilityRoleText, label: "Label"},
+	}
+	for _, tc := range testCases {
+		node, ok := findAccessibilityNode(snapshot, func(node *guigui.AccessibilityNode) bool {
+			return node.Role == tc.role && node.Label == tc.label
+		})
+		if !ok {
+			t.Errorf("no node with role %q and label %q", tc.role, tc.label)
+			continue
+		}
+		if node.Checked != tc.checked {
+			t.Errorf("%q %q: Checked: got: %t, want: %t", tc.role, tc.label, node.Checked, tc.checked)
+		}
+		if node.Selected != tc.selected {
+			t.Errorf("%q %q: Selected: got: %t, want: %t", tc.role, tc.label, node.Selected, tc.selected)
+		}
+		// Leaves hide their presentational descendants.
+		if len(node.Children) > 0 {
+			t.Errorf("%q %q: len(Children): got: %d, want: 0", tc.role, tc.label, len(node.Children))
+		}
+	}
+
+	// The list items are the children of the list.
+	list, ok := findAccessibilityNode(snapshot, func(node *guigui.AccessibilityNode) bool {
+		return node.Role == guigui.AccessibilityRoleList
+	})
+	if !ok {
+		t.Fatalf("no list node")
+	}
+	var labels []string
+	for _, child := range list.Children {
+		if child.Role == guigui.AccessibilityRoleListItem {
+			labels = append(labels, child.Label)
+		}
+	}
+	if want := []string{"Foo", "Bar"}; !slices.Equal(labels, want) {
+		t.Errorf("list item labels: got: %q, want: %q", labels, want)
+	}
+}
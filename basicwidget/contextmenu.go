@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+// ContextMenuItem is an item of a context menu.
+type ContextMenuItem struct {
+	Text     string
+	Disabled bool
+
+	// Border reports whether the item is a separator.
+	Border bool
+
+	// OnSelected is called when the item is selected.
+	OnSelected func()
+}
+
+// contextMenu is a popup menu opened by a right click, the Menu key, or Shift+F10.
+type contextMenu struct {
+	popupMenu PopupMenu
+	items     []ContextMenuItem
+}
+
+// isContextMenuKeyJustPressed reports whether the Menu key or Shift+F10 is just pressed.
+func isContextMenuKeyJustPressed(input *guigui.InputState) bool {
+	return input.IsKeyJustPressed(ebiten.KeyContextMenu) ||
+		input.IsKeyPressed(ebiten.KeyShift) && input.IsKeyJustPressed(ebiten.KeyF10)
+}
+
+// open opens the menu with the items at the position. If items is empty, open does nothing.
+func (c *contextMenu) open(context *guigui.Context, position image.Point, items []ContextMenuItem) bool {
+	if len(items) == 0 {
+		return false
+	}
+
+	c.items = append(slices.Delete(c.items, 0, len(c.items)), items...)
+	menuItems := make([]PopupMenuItem, len(items))
+	for i, item := range items {
+		menuItems[i] = PopupMenuItem{
+			Text:      item.Text,
+			Disabled:  item.Disabled,
+			Separator: item.Border,
+		}
+	}
+	c.popupMenu.SetItems(menuItems)
+	c.popupMenu.SetOnClosed(func(index int) {
+		if index < 0 || index >= len(c.items) {
+			return
+		}
+		if f := c.items[index].OnSelected; f != nil {
+			f()
+		}
+	})
+	guigui.SetPosition(&c.popupMenu, position)
+	c.popupMenu.Open(context)
+	return true
+}
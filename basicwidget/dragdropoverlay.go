@@ -4,10 +4,7 @@
 package basicwidget
 
 import (
-	"image"
-
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"github.com/xackery/guigui"
 )
@@ -34,8 +31,9 @@ func (d *DragDropOverlay) Start(object any) {
 
 func (d *DragDropOverlay) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 	if d.object != nil {
-		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-			if image.Pt(ebiten.CursorPosition()).In(guigui.VisibleBounds(d)) {
+		input := context.Input()
+		if input.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+			if input.CursorPosition().In(guigui.VisibleBounds(d)) {
 				if d.onDropped != nil {
 					d.onDropped(d.object)
 				}
@@ -43,7 +41,7 @@ func (d *DragDropOverlay) HandleInput(context *guigui.Context) guigui.HandleInpu
 			d.object = nil
 			return guigui.HandleInputResult{}
 		}
-		if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if !input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 			d.object = nil
 		}
 		return guigui.HandleInputResult{}
@@ -57,7 +57,34 @@ func (d *DropdownList) SelectedItemIndex() int {
 }
 
 func (d *DropdownList) SetSelectedItemIndex(index int) {
+	if d.popupMenu.SelectedItemIndex() == index {
+		return
+	}
 	d.popupMenu.SetSelectedItemIndex(index)
+	// The button shows the selected item's text.
+	guigui.RequestRelayout(d)
+}
+
+func (d *DropdownList) Accessibility(context *guigui.Context) guigui.Accessibility {
+	a := guigui.Accessibility{
+		Role:     guigui.AccessibilityRoleComboBox,
+		Expanded: guigui.IsVisible(&d.popupMenu.popup),
+		Actions:  []guigui.AccessibilityAction{guigui.AccessibilityActionPress},
+	}
+	if item, ok := d.popupMenu.SelectedItem(); ok {
+		a.Value = item.Text
+	}
+	// Expose the menu items only while the menu is open.
+	a.Leaf = !a.Expanded
+	return a
+}
+
+func (d *DropdownList) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress {
+		return false
+	}
+	d.textButton.button.press()
+	return true
 }
 
 func (d *DropdownList) Size(context *guigui.Context) (int, int) {
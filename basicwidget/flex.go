@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"slices"
+
+	"github.com/xackery/guigui"
+)
+
+// FlexItem is an item of a Flex.
+type FlexItem struct {
+	Widget guigui.Widget
+
+	// Grow is the weight to take the extra space along the main axis.
+	// If Grow is 0, the widget doesn't grow.
+	Grow float64
+
+	// Shrink is the weight to give up the space along the main axis when the widgets overflow.
+	// The actual amount is also proportional to the widget's size.
+	// If Shrink is 0, the widget doesn't shrink.
+	Shrink float64
+}
+
+// Flex arranges widgets in a row or a column, and distributes the extra space to the widgets by their weights.
+//
+// A widget is resized only when it has a SetSize or SetWidth method.
+// The widgets in a Flex must not be appended by other widgets.
+type Flex struct {
+	guigui.DefaultWidget
+
+	items  []FlexItem
+	layout flexLayout
+}
+
+// SetItems sets the items.
+func (f *Flex) SetItems(items []FlexItem) {
+	if slices.Equal(f.items, items) {
+		return
+	}
+	f.items = append(slices.Delete(f.items, 0, len(f.items)), items...)
+	guigui.RequestRelayout(f)
+}
+
+// SetDirection sets the direction of the main axis. The default is vertical.
+func (f *Flex) SetDirection(direction LayoutDirection) {
+	if f.layout.direction == direction {
+		return
+	}
+	f.layout.direction = direction
+	guigui.RequestRelayout(f)
+}
+
+// SetGap sets the space between the widgets.
+func (f *Flex) SetGap(gap int) {
+	if f.layout.gap == gap {
+		return
+	}
+	f.layout.gap = gap
+	guigui.RequestRelayout(f)
+}
+
+// SetAlignment sets the alignment of the widgets along the cross axis.
+func (f *Flex) SetAlignment(alignment LayoutAlignment) {
+	if f.layout.alignment == alignment {
+		return
+	}
+	f.layout.alignment = alignment
+	guigui.RequestRelayout(f)
+}
+
+// SetSize sets the size. Without a size, the Flex is as large as its widgets.
+func (f *Flex) SetSize(context *guigui.Context, width, height int) {
+	if f.layout.setSize(width, height) {
+		guigui.RequestRelayout(f)
+	}
+}
+
+// ResetSize resets the size set by SetSize.
+func (f *Flex) ResetSize() {
+	if f.layout.resetSize() {
+		guigui.RequestRelayout(f)
+	}
+}
+
+func (f *Flex) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	f.layout.layout(context, guigui.Bounds(f), f.items, appender)
+}
+
+// Measure implements guigui.Measurer. The sizes are by the widgets, and don't depend on the size set by SetSize.
+func (f *Flex) Measure(context *guigui.Context, constraints guigui.Constraints) guigui.Measurement {
+	return f.layout.measure(context, f.items, constraints)
+}
+
+func (f *Flex) Size(context *guigui.Context) (int, int) {
+	return f.layout.size(context, f)
+}
+
+// flexLayout is the implementation of Flex and Stack.
+type flexLayout struct {
+	direction LayoutDirection
+	gap       int
+	alignment LayoutAlignment
+
+	sizeSet bool
+	width   int
+	height  int
+
+	sizer    layoutSizer
+	visible  []FlexItem
+	mains    []int
+	minMains []int
+	weights  []float64
+}
+
+// setSize sets the size, and reports whether the size is changed.
+func (f *flexLayout) setSize(width, height int) bool {
+	if f.sizeSet && f.width == width && f.height == height {
+		return false
+	}
+	f.sizeSet = true
+	f.width = width
+	f.height = height
+	return true
+}
+
+// resetSize resets the size, and reports whether the size is changed.
+func (f *flexLayout) resetSize() bool {
+	if !f.sizeSet {
+		return false
+	}
+	f.sizeSet = false
+	f.width = 0
+	f.height = 0
+	return true
+}
+
+func (f *flexLayout) mainCross(x, y int) (int, int) {
+	if f.direction == LayoutDirectionHorizontal {
+		return x, y
+	}
+	return y, x
+}
+
+func (f *flexLayout) xy(main, cross int) (int, int) {
+	// mainCross is its own inverse.
+	return f.mainCross(main, cross)
+}
+
+// itemConstraints returns the constraints to measure the items.
+func (f *flexLayout) itemConstraints(maxMain, maxCross int) guigui.Constraints {
+	w, h := f.xy(maxMain, maxCross)
+	return guigui.LooseConstraints(w, h)
+}
+
+func (f *flexLayout) measure(context *guigui.Context, items []FlexItem, constraints guigui.Constraints) guigui.Measurement {
+	_, maxCross := f.mainCross(constraints.Max.X, constraints.Max.Y)
+	c := f.itemConstraints(guigui.Unbounded, maxCross)
+
+	var main, cross, minMain, minCross, n int
+	for _, item := range items {
+		if item.Widget == nil || !guigui.IsVisible(item.Widget) {
+			continue
+		}
+		m := f.sizer.measure(context, item.Widget, c)
+		pm, pc := f.mainCross(m.Preferred.X, m.Preferred.Y)
+		mm, mc := f.mainCross(m.Min.X, m.Min.Y)
+		main += pm
+		cross = max(cross, pc)
+		minMain += mm
+		minCross = max(minCross, mc)
+		n++
+	}
+	if n > 0 {
+		main += (n - 1) * f.gap
+		minMain += (n - 1) * f.gap
+	}
+	var result guigui.Measurement
+	result.Preferred.X, result.Preferred.Y = f.xy(main, cross)
+	result.Min.X, result.Min.Y = f.xy(minMain, minCross)
+	result.Max = image.Pt(guigui.Unbounded, guigui.Unbounded)
+	return result
+}
+
+func (f *flexLayout) size(context *guigui.Context, widget guigui.Widget) (int, int) {
+	if f.sizeSet {
+		return f.width, f.height
+	}
+	m := guigui.Measure(context, widget, guigui.UnboundedConstraints())
+	return m.Preferred.X, m.Preferred.Y
+}
+
+func (f *flexLayout) layout(context *guigui.Context, bounds image.Rectangle, items []FlexItem, appender *guigui.ChildWidgetAppender) {
+	f.sizer.reset()
+
+	mainSpace, crossSpace := f.mainCross(bounds.Dx(), bounds.Dy())
+	c := f.itemConstraints(guigui.Unbounded, crossSpace)
+
+	f.visible = slices.Delete(f.visible, 0, len(f.visible))
+	f.mains = slices.Delete(f.mains, 0, len(f.mains))
+	f.minMains = slices.Delete(f.minMains, 0, len(f.minMains))
+	f.weights = slices.Delete(f.weights, 0, len(f.weights))
+	free := mainSpace
+	for _, item := range items {
+		if item.Widget == nil {
+			continue
+		}
+		if !guigui.IsVisible(item.Widget) {
+			// Keep the hidden widgets as children so that they can be shown again.
+			appender.AppendChildWidget(item.Widget)
+			continue
+		}
+		m := f.sizer.measure(context, item.Widget, c)
+		pm, _ := f.mainCross(m.Preferred.X, m.Preferred.Y)
+		mm, _ := f.mainCross(m.Min.X, m.Min.Y)
+		f.visible = append(f.visible, item)
+		f.mains = append(f.mains, pm)
+		f.minMains = append(f.minMains, mm)
+		free -= pm
+	}
+	if len(f.visible) > 0 {
+		free -= (len(f.visible) - 1) * f.gap
+	}
+
+	// Distribute the extra space, or take the overflowing space away.
+	if free > 0 {
+		for _, item := range f.visible {
+			f.weights = append(f.weights, max(item.Grow, 0))
+		}
+		distribute(free, f.weights, f.mains)
+	} else if free < 0 {
+		for i, item := range f.visible {
+			f.weights = append(f.weights, max(item.Shrink, 0)*float64(f.mains[i]))
+		}
+		distribute(free, f.weights, f.mains)
+		for i := range f.mains {
+			f.mains[i] = max(f.mains[i], f.minMains[i])
+		}
+	}
+
+	mainPos, crossPos := f.mainCross(bounds.Min.X, bounds.Min.Y)
+	for i, item := range f.visible {
+		// Measure the item again with the decided main size, as the cross size might depend on it.
+		m := f.sizer.measure(context, item.Widget, f.itemConstraints(f.mains[i], crossSpace))
+		_, c := f.mainCross(m.Preferred.X, m.Preferred.Y)
+		_, c = f.alignment.align(crossSpace, c)
+		w, h := f.xy(f.mains[i], c)
+		w, h = f.sizer.setSize(context, item.Widget, w, h)
+		_, c = f.mainCross(w, h)
+		offset, _ := f.alignment.align(crossSpace, c)
+		guigui.SetPosition(item.Widget, image.Pt(f.xy(mainPos, crossPos+offset)))
+		appender.AppendChildWidget(item.Widget)
+		mainPos += f.mains[i] + f.gap
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+// isActivationKeyJustPressed reports whether a key to press a focused button is just pressed.
+func isActivationKeyJustPressed(input *guigui.InputState) bool {
+	return input.IsKeyJustPressed(ebiten.KeySpace) ||
+		input.IsKeyJustPressed(ebiten.KeyEnter) ||
+		input.IsKeyJustPressed(ebiten.KeyNumpadEnter)
+}
+
+func focusRingWidth(context *guigui.Context) int {
+	return int(3 * context.Scale())
+}
+
+// focusRing is a ring drawn around the parent widget focused by keyboard navigation.
+//
+// A focusRing is a popup so that the ring is not clipped by the parent.
+type focusRing struct {
+	guigui.DefaultWidget
+}
+
+// appendFocusRing appends the focus ring as a child widget if the widget is focused.
+func appendFocusRing(context *guigui.Context, appender *guigui.ChildWidgetAppender, ring *focusRing, widget guigui.Widget) {
+	if !guigui.IsFocused(widget) {
+		return
+	}
+	w := focusRingWidth(context)
+	guigui.SetPosition(ring, guigui.Position(widget).Add(image.Pt(-w, -w)))
+	appender.AppendChildWidget(ring)
+}
+
+func (f *focusRing) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(guigui.Parent(f))
+	w := focusRingWidth(context)
+	bounds = bounds.Inset(-w)
+	clr := Color(context.ColorMode(), ColorTypeAccent, 0.8)
+	r := min(RoundedCornerRadius(context), bounds.Dx()/4, bounds.Dy()/4)
+	DrawRoundedRectBorder(context, dst, bounds, clr, int(4*context.Scale())+r, float32(4*context.Scale()), RoundedRectBorderTypeRegular)
+}
+
+func (f *focusRing) IsPopup() bool {
+	return true
+}
+
+func (f *focusRing) Size(context *guigui.Context) (int, int) {
+	w, h := guigui.Parent(f).Size(context)
+	w += 2 * focusRingWidth(context)
+	h += 2 * focusRingWidth(context)
+	return w, h
+}
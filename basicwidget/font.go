@@ -9,6 +9,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/go-text/typesetting/segmenter"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
@@ -238,3 +239,153 @@ func nextPositionOnClusters(str string, face text.Face, position int) int {
 	}
 	return position
 }
+
+func graphemeOffsets(str string) []int {
+	runes := make([]rune, 0, len(str))
+	offsets := make([]int, 0, len(str)+1)
+	for i, r := range str {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(str))
+
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	result := []int{0}
+	iter := seg.GraphemeIterator()
+	for iter.Next() {
+		g := iter.Grapheme()
+		result = append(result, offsets[g.Offset+len(g.Text)])
+	}
+	return result
+}
+
+// textLength returns the length of str in the unit.
+func textLength(str string, unit TextLengthUnit) int {
+	switch unit {
+	case TextLengthUnitRune:
+		return utf8.RuneCountInString(str)
+	default:
+		return len(graphemeOffsets(str)) - 1
+	}
+}
+
+// truncateText returns the longest prefix of str whose length in the unit is at most length.
+func truncateText(str string, length int, unit TextLengthUnit) string {
+	if length <= 0 {
+		return ""
+	}
+	switch unit {
+	case TextLengthUnitRune:
+		var n int
+		for i := range str {
+			if n == length {
+				return str[:i]
+			}
+			n++
+		}
+		return str
+	default:
+		offsets := graphemeOffsets(str)
+		if length >= len(offsets)-1 {
+			return str
+		}
+		return str[:offsets[length]]
+	}
+}
+
+type textRange struct {
+	start int
+	end   int
+}
+
+// words returns the ranges of the words in bytes by the Unicode word boundary rules (UAX #29).
+// The spaces and the punctuations between words are not included.
+func words(str string) []textRange {
+	return appendWords(nil, str, 0)
+}
+
+func appendWords(ranges []textRange, str string, offset int) []textRange {
+	runes := make([]rune, 0, len(str))
+	offsets := make([]int, 0, len(str)+1)
+	for i, r := range str {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(str))
+
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	iter := seg.WordIterator()
+	// WordIterator skips a word starting just at the end of another word, e.g. the second one of consecutive ideographs.
+	// Such a word is in the gap after a word, so segment the gap again.
+	lastEnd := -1
+	for iter.Next() {
+		w := iter.Word()
+		start, end := offsets[w.Offset], offsets[w.Offset+len(w.Text)]
+		if lastEnd >= 0 && lastEnd < start {
+			ranges = appendWords(ranges, str[lastEnd:start], offset+lastEnd)
+		}
+		ranges = append(ranges, textRange{
+			start: offset + start,
+			end:   offset + end,
+		})
+		lastEnd = end
+	}
+	if lastEnd >= 0 && lastEnd < len(str) {
+		ranges = appendWords(ranges, str[lastEnd:], offset+lastEnd)
+	}
+	return ranges
+}
+
+func prevWordPosition(str string, position int) int {
+	var result int
+	for _, w := range words(str) {
+		if w.start >= position {
+			break
+		}
+		result = w.start
+	}
+	return result
+}
+
+func nextWordPosition(str string, position int) int {
+	for _, w := range words(str) {
+		if w.end > position {
+			return w.end
+		}
+	}
+	return len(str)
+}
+
+// wordRangeAt returns the range of the word at the position.
+// If the position is not in a word, wordRangeAt returns the range between the words.
+func wordRangeAt(str string, position int) (int, int) {
+	ws := words(str)
+	var gapStart int
+	for _, w := range ws {
+		if position < w.start {
+			return gapStart, w.start
+		}
+		if position < w.end {
+			return w.start, w.end
+		}
+		gapStart = w.end
+	}
+	// The position is just after the last word.
+	if len(ws) > 0 && gapStart == len(str) {
+		w := ws[len(ws)-1]
+		return w.start, w.end
+	}
+	return gapStart, len(str)
+}
+
+// lineRangeAt returns the range of the line at the position without the line break.
+func lineRangeAt(str string, position int) (int, int) {
+	start := strings.LastIndex(str[:position], "\n") + 1
+	end := len(str)
+	if i := strings.Index(str[position:], "\n"); i >= 0 {
+		end = position + i
+	}
+	return start, end
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"testing"
+)
+
+func TestPrevAndNextWordPosition(t *testing.T) {
+	testCases := []struct {
+		str      string
+		position int
+		prev     int
+		next     int
+	}{
+		{str: "", position: 0, prev: 0, next: 0},
+		{str: "Hello, world!", position: 0, prev: 0, next: 5},
+		{str: "Hello, world!", position: 3, prev: 0, next: 5},
+		{str: "Hello, world!", position: 5, prev: 0, next: 12},
+		{str: "Hello, world!", position: 7, prev: 0, next: 12},
+		{str: "Hello, world!", position: 9, prev: 7, next: 12},
+		{str: "Hello, world!", position: 13, prev: 7, next: 13},
+		{str: "can't stop", position: 0, prev: 0, next: 5},
+		{str: "can't stop", position: 10, prev: 6, next: 10},
+		{str: "foo.bar 3.14", position: 0, prev: 0, next: 7},
+		{str: "foo.bar 3.14", position: 7, prev: 0, next: 12},
+
+		// Each ideograph is a word.
+		{str: "日本語のテキスト", position: 0, prev: 0, next: 3},
+		{str: "日本語のテキスト", position: 3, prev: 0, next: 6},
+		{str: "日本語のテキスト", position: 6, prev: 3, next: 9},
+		{str: "日本語のテキスト", position: 12, prev: 9, next: 24},
+		{str: "日本語のテキスト", position: 24, prev: 12, next: 24},
+		{str: "中文字符", position: 9, prev: 6, next: 12},
+		{str: "hello 世界 ok", position: 5, prev: 0, next: 9},
+		{str: "hello 世界 ok", position: 9, prev: 6, next: 12},
+		{str: "hello 世界 ok", position: 12, prev: 9, next: 15},
+		{str: "한국어 텍스트", position: 9, prev: 0, next: 19},
+	}
+	for _, tc := range testCases {
+		if got := prevWordPosition(tc.str, tc.position); got != tc.prev {
+			t.Errorf("prevWordPosition(%q, %d): got: %d, want: %d", tc.str, tc.position, got, tc.prev)
+		}
+		if got := nextWordPosition(tc.str, tc.position); got != tc.next {
+			t.Errorf("nextWordPosition(%q, %d): got: %d, want: %d", tc.str, tc.position, got, tc.next)
+		}
+	}
+}
+
+func TestWordRangeAt(t *testing.T) {
+	testCases := []struct {
+		str      string
+		position int
+		start    int
+		end      int
+	}{
+		{str: "", position: 0, start: 0, end: 0},
+		{str: "Hello, world!", position: 0, start: 0, end: 5},
+		{str: "Hello, world!", position: 4, start: 0, end: 5},
+		{str: "Hello, world!", position: 5, start: 5, end: 7},
+		{str: "Hello, world!", position: 6, start: 5, end: 7},
+		{str: "Hello, world!", position: 7, start: 7, end: 12},
+		{str: "Hello, world!", position: 12, start: 12, end: 13},
+		{str: "Hello world", position: 11, start: 6, end: 11},
+		{str: "can't stop", position: 3, start: 0, end: 5},
+		{str: "   ", position: 1, start: 0, end: 3},
+
+		{str: "日本語のテキスト", position: 0, start: 0, end: 3},
+		{str: "日本語のテキスト", position: 4, start: 3, end: 6},
+		{str: "日本語のテキスト", position: 9, start: 9, end: 12},
+		{str: "日本語のテキスト", position: 15, start: 12, end: 24},
+		{str: "日本語のテキスト", position: 24, start: 12, end: 24},
+		{str: "hello 世界 ok", position: 10, start: 9, end: 12},
+		{str: "hello 世界 ok", position: 12, start: 12, end: 13},
+		{str: "한국어 텍스트", position: 12, start: 10, end: 19},
+	}
+	for _, tc := range testCases {
+		start, end := wordRangeAt(tc.str, tc.position)
+		if start != tc.start || end != tc.end {
+			t.Errorf("wordRangeAt(%q, %d): got: (%d, %d), want: (%d, %d)", tc.str, tc.position, start, end, tc.start, tc.end)
+		}
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	const str = "ae\u0301\U0001F1EF\U0001F1F5b"
+	testCases := []struct {
+		length int
+		unit   TextLengthUnit
+		want   string
+	}{
+		{length: 0, unit: TextLengthUnitGrapheme, want: ""},
+		{length: 1, unit: TextLengthUnitGrapheme, want: "a"},
+		{length: 2, unit: TextLengthUnitGrapheme, want: "ae\u0301"},
+		{length: 3, unit: TextLengthUnitGrapheme, want: "ae\u0301\U0001F1EF\U0001F1F5"},
+		{length: 4, unit: TextLengthUnitGrapheme, want: str},
+		{length: 5, unit: TextLengthUnitGrapheme, want: str},
+		{length: 2, unit: TextLengthUnitRune, want: "ae"},
+		{length: 4, unit: TextLengthUnitRune, want: "ae\u0301\U0001F1EF"},
+	}
+	for _, tc := range testCases {
+		if got := truncateText(str, tc.length, tc.unit); got != tc.want {
+			t.Errorf("truncateText(%q, %d, %d): got: %q, want: %q", str, tc.length, tc.unit, got, tc.want)
+		}
+	}
+}
@@ -17,10 +17,13 @@ type FormItem struct {
 	SecondaryWidget guigui.Widget
 }
 
+// Form is a list of pairs of a primary widget like a label and a secondary widget like an input.
+//
+// A primary widget that is a *Text is truncated with an ellipsis when it doesn't fit with the secondary widget.
 type Form struct {
 	guigui.DefaultWidget
 
-	items             []*FormItem
+	items             []FormItem
 	widthMinusDefault int
 
 	primaryBounds   []image.Rectangle
@@ -31,12 +34,24 @@ func formItemPadding(context *guigui.Context) (int, int) {
 	return UnitSize(context) / 2, UnitSize(context) / 4
 }
 
+// SetItems sets the items.
+//
+// The items are copied, so call SetItems again after modifying the items.
 func (f *Form) SetItems(items []*FormItem) {
+	if slices.EqualFunc(f.items, items, func(a FormItem, b *FormItem) bool {
+		return a == *b
+	}) {
+		return
+	}
 	f.items = slices.Delete(f.items, 0, len(f.items))
-	f.items = append(f.items, items...)
+	for _, item := range items {
+		f.items = append(f.items, *item)
+	}
+	guigui.RequestRelayout(f)
 }
 
 func (f *Form) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	f.truncatePrimaryTexts(context)
 	f.calcItemBounds(context)
 
 	for i, item := range f.items {
@@ -58,6 +73,28 @@ func (f *Form) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppen
 	}
 }
 
+// truncatePrimaryTexts sets the widths of the primary texts so that the texts don't overlap with the secondary widgets.
+func (f *Form) truncatePrimaryTexts(context *guigui.Context) {
+	paddingX, _ := formItemPadding(context)
+	w, _ := f.Size(context)
+	for _, item := range f.items {
+		text, ok := item.PrimaryWidget.(*Text)
+		if !ok {
+			continue
+		}
+		maxW := w - 2*paddingX
+		if item.SecondaryWidget != nil {
+			sw, _ := item.SecondaryWidget.Size(context)
+			maxW -= sw + paddingX
+		}
+		if text.truncation == TextTruncationNone {
+			text.SetTruncation(TextTruncationEnd)
+		}
+		tw, _ := text.TextSize(context)
+		text.SetWidth(min(tw, max(maxW, 0)))
+	}
+}
+
 func (f *Form) calcItemBounds(context *guigui.Context) {
 	f.primaryBounds = slices.Delete(f.primaryBounds, 0, len(f.primaryBounds))
 	f.secondaryBounds = slices.Delete(f.secondaryBounds, 0, len(f.secondaryBounds))
@@ -153,7 +190,12 @@ func (f *Form) Draw(context *guigui.Context, dst *ebiten.Image) {
 }
 
 func (f *Form) SetWidth(context *guigui.Context, width int) {
-	f.widthMinusDefault = width - defaultFormWidth(context)
+	w := width - defaultFormWidth(context)
+	if f.widthMinusDefault == w {
+		return
+	}
+	f.widthMinusDefault = w
+	guigui.RequestRelayout(f)
 }
 
 func (f *Form) Size(context *guigui.Context) (int, int) {
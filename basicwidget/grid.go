@@ -0,0 +1,359 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"slices"
+
+	"github.com/xackery/guigui"
+)
+
+// GridTrackType is the way to decide the size of a column or a row.
+type GridTrackType int
+
+const (
+	// GridTrackTypeAuto makes the track as large as its widgets.
+	GridTrackTypeAuto GridTrackType = iota
+
+	// GridTrackTypeFixed makes the track as large as GridTrack.Size.
+	GridTrackTypeFixed
+
+	// GridTrackTypeFraction makes the track take the rest of the space by GridTrack.Fraction.
+	// Without a size of the Grid, the track is as large as its widgets.
+	GridTrackTypeFraction
+)
+
+// GridTrack is a column or a row of a Grid.
+type GridTrack struct {
+	Type     GridTrackType
+	Size     int
+	Fraction float64
+}
+
+// GridItem is an item of a Grid.
+type GridItem struct {
+	Widget guigui.Widget
+
+	// Column and Row are the zero-based indices of the cell.
+	Column int
+	Row    int
+
+	// ColumnSpan and RowSpan are the numbers of the cells the widget covers.
+	// 0 is treated as 1.
+	ColumnSpan int
+	RowSpan    int
+
+	HorizontalAlignment LayoutAlignment
+	VerticalAlignment   LayoutAlignment
+}
+
+func (g *GridItem) columnSpan() int {
+	return max(g.ColumnSpan, 1)
+}
+
+func (g *GridItem) rowSpan() int {
+	return max(g.RowSpan, 1)
+}
+
+// Grid arranges widgets in cells of columns and rows.
+//
+// The columns and the rows not specified by SetColumns and SetRows are treated as auto tracks.
+// The widgets in a Grid must not be appended by other widgets.
+type Grid struct {
+	guigui.DefaultWidget
+
+	items     []GridItem
+	columns   []GridTrack
+	rows      []GridTrack
+	columnGap int
+	rowGap    int
+
+	sizeSet bool
+	width   int
+	height  int
+
+	sizer          layoutSizer
+	columnSizes    []int
+	rowSizes       []int
+	naturalWidths  []int
+	naturalHeights []int
+}
+
+// SetItems sets the items.
+func (g *Grid) SetItems(items []GridItem) {
+	if slices.Equal(g.items, items) {
+		return
+	}
+	g.items = append(slices.Delete(g.items, 0, len(g.items)), items...)
+	guigui.RequestRelayout(g)
+}
+
+// SetColumns sets the columns.
+func (g *Grid) SetColumns(columns []GridTrack) {
+	if slices.Equal(g.columns, columns) {
+		return
+	}
+	g.columns = append(slices.Delete(g.columns, 0, len(g.columns)), columns...)
+	guigui.RequestRelayout(g)
+}
+
+// SetRows sets the rows.
+func (g *Grid) SetRows(rows []GridTrack) {
+	if slices.Equal(g.rows, rows) {
+		return
+	}
+	g.rows = append(slices.Delete(g.rows, 0, len(g.rows)), rows...)
+	guigui.RequestRelayout(g)
+}
+
+// SetGap sets the spaces between the columns and between the rows.
+func (g *Grid) SetGap(columnGap, rowGap int) {
+	if g.columnGap == columnGap && g.rowGap == rowGap {
+		return
+	}
+	g.columnGap = columnGap
+	g.rowGap = rowGap
+	guigui.RequestRelayout(g)
+}
+
+// SetSize sets the size. Without a size, the Grid is as large as its widgets.
+func (g *Grid) SetSize(context *guigui.Context, width, height int) {
+	if g.sizeSet && g.width == width && g.height == height {
+		return
+	}
+	g.sizeSet = true
+	g.width = width
+	g.height = height
+	guigui.RequestRelayout(g)
+}
+
+// ResetSize resets the size set by SetSize.
+func (g *Grid) ResetSize() {
+	if !g.sizeSet {
+		return
+	}
+	g.sizeSet = false
+	g.width = 0
+	g.height = 0
+	guigui.RequestRelayout(g)
+}
+
+func (g *Grid) trackCounts() (int, int) {
+	columnCount := len(g.columns)
+	rowCount := len(g.rows)
+	for _, item := range g.items {
+		columnCount = max(columnCount, item.Column+item.columnSpan())
+		rowCount = max(rowCount, item.Row+item.rowSpan())
+	}
+	return columnCount, rowCount
+}
+
+func (g *Grid) track(tracks []GridTrack, index int) GridTrack {
+	if index < len(tracks) {
+		return tracks[index]
+	}
+	return GridTrack{}
+}
+
+func (g *Grid) isItemVisible(item *GridItem) bool {
+	return item.Widget != nil && guigui.IsVisible(item.Widget) && item.Column >= 0 && item.Row >= 0
+}
+
+// calcTrackSizes calculates the sizes of the columns and the rows.
+// If space is negative, fractional tracks are as large as their widgets.
+func (g *Grid) calcTrackSizes(context *guigui.Context, spaceX, spaceY int) {
+	columnCount, rowCount := g.trackCounts()
+
+	g.naturalWidths = slices.Delete(g.naturalWidths, 0, len(g.naturalWidths))
+	for _, item := range g.items {
+		var w int
+		if g.isItemVisible(&item) {
+			w = g.sizer.measure(context, item.Widget, guigui.UnboundedConstraints()).Preferred.X
+		}
+		g.naturalWidths = append(g.naturalWidths, w)
+	}
+	g.columnSizes = g.calcTracks(g.columnSizes[:0], g.columns, columnCount, g.columnGap, spaceX, func(item *GridItem) (int, int) {
+		return item.Column, item.columnSpan()
+	}, g.naturalWidths)
+
+	// Measure the heights with the widths of the cells, as the heights might depend on the widths.
+	g.naturalHeights = slices.Delete(g.naturalHeights, 0, len(g.naturalHeights))
+	for _, item := range g.items {
+		var h int
+		if g.isItemVisible(&item) {
+			w := g.cellBounds(image.Point{}, &item).Dx()
+			h = g.sizer.measure(context, item.Widget, guigui.LooseConstraints(w, guigui.Unbounded)).Preferred.Y
+		}
+		g.naturalHeights = append(g.naturalHeights, h)
+	}
+	g.rowSizes = g.calcTracks(g.rowSizes[:0], g.rows, rowCount, g.rowGap, spaceY, func(item *GridItem) (int, int) {
+		return item.Row, item.rowSpan()
+	}, g.naturalHeights)
+}
+
+func (g *Grid) calcTracks(sizes []int, tracks []GridTrack, count int, gap int, space int, cells func(item *GridItem) (int, int), naturalSizes []int) []int {
+	for i := range count {
+		var size int
+		if t := g.track(tracks, i); t.Type == GridTrackTypeFixed {
+			size = t.Size
+		}
+		sizes = append(sizes, size)
+	}
+
+	isFlexible := func(i int) bool {
+		t := g.track(tracks, i)
+		return t.Type == GridTrackTypeAuto || t.Type == GridTrackTypeFraction && space < 0
+	}
+
+	// Fit the flexible tracks to the widgets in a single cell first, and then to the widgets spanning the cells.
+	for _, spanning := range []bool{false, true} {
+		for i := range g.items {
+			item := &g.items[i]
+			if !g.isItemVisible(item) {
+				continue
+			}
+			start, span := cells(item)
+			if span > 1 != spanning {
+				continue
+			}
+			end := min(start+span, count)
+			if start >= end {
+				continue
+			}
+			var current int
+			var flexibleCount int
+			for j := start; j < end; j++ {
+				current += sizes[j]
+				if isFlexible(j) {
+					flexibleCount++
+				}
+			}
+			current += (end - start - 1) * gap
+			extra := naturalSizes[i] - current
+			if extra <= 0 || flexibleCount == 0 {
+				continue
+			}
+			for j := start; j < end; j++ {
+				if !isFlexible(j) {
+					continue
+				}
+				d := extra / flexibleCount
+				sizes[j] += d
+				extra -= d
+				flexibleCount--
+			}
+		}
+	}
+
+	if space < 0 {
+		return sizes
+	}
+
+	// Distribute the rest of the space to the fractional tracks.
+	rest := space
+	var weights []float64
+	for i := range count {
+		t := g.track(tracks, i)
+		if t.Type == GridTrackTypeFraction {
+			weights = append(weights, max(t.Fraction, 0))
+			continue
+		}
+		weights = append(weights, 0)
+		rest -= sizes[i]
+	}
+	if count > 0 {
+		rest -= (count - 1) * gap
+	}
+	distribute(max(rest, 0), weights, sizes)
+	return sizes
+}
+
+func (g *Grid) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	g.sizer.reset()
+
+	bounds := guigui.Bounds(g)
+	if g.sizeSet {
+		g.calcTrackSizes(context, bounds.Dx(), bounds.Dy())
+	} else {
+		g.calcTrackSizes(context, -1, -1)
+	}
+
+	for i := range g.items {
+		item := &g.items[i]
+		if item.Widget == nil {
+			continue
+		}
+		if !g.isItemVisible(item) {
+			// Keep the hidden widgets as children so that they can be shown again.
+			appender.AppendChildWidget(item.Widget)
+			continue
+		}
+
+		cell := g.cellBounds(bounds.Min, item)
+		nw, nh := g.naturalWidths[i], g.naturalHeights[i]
+		_, w := item.HorizontalAlignment.align(cell.Dx(), nw)
+		_, h := item.VerticalAlignment.align(cell.Dy(), nh)
+		w, h = g.sizer.setSize(context, item.Widget, w, h)
+		x, _ := item.HorizontalAlignment.align(cell.Dx(), w)
+		y, _ := item.VerticalAlignment.align(cell.Dy(), h)
+		guigui.SetPosition(item.Widget, cell.Min.Add(image.Pt(x, y)))
+		appender.AppendChildWidget(item.Widget)
+	}
+}
+
+func (g *Grid) cellBounds(origin image.Point, item *GridItem) image.Rectangle {
+	spanBounds := func(sizes []int, gap int, start, span int) (int, int) {
+		var pos int
+		for i := 0; i < start && i < len(sizes); i++ {
+			pos += sizes[i] + gap
+		}
+		var size int
+		for i := start; i < start+span && i < len(sizes); i++ {
+			if i > start {
+				size += gap
+			}
+			size += sizes[i]
+		}
+		return pos, size
+	}
+	x, w := spanBounds(g.columnSizes, g.columnGap, item.Column, item.columnSpan())
+	y, h := spanBounds(g.rowSizes, g.rowGap, item.Row, item.rowSpan())
+	return image.Rect(x, y, x+w, y+h).Add(origin)
+}
+
+// Measure implements guigui.Measurer. The sizes are by the widgets, and don't depend on the size set by SetSize.
+func (g *Grid) Measure(context *guigui.Context, constraints guigui.Constraints) guigui.Measurement {
+	spaceX := -1
+	if constraints.HasBoundedWidth() {
+		spaceX = constraints.Max.X
+	}
+	g.calcTrackSizes(context, spaceX, -1)
+	var w, h int
+	for _, s := range g.columnSizes {
+		w += s
+	}
+	for _, s := range g.rowSizes {
+		h += s
+	}
+	if len(g.columnSizes) > 0 {
+		w += (len(g.columnSizes) - 1) * g.columnGap
+	}
+	if len(g.rowSizes) > 0 {
+		h += (len(g.rowSizes) - 1) * g.rowGap
+	}
+	return guigui.Measurement{
+		Preferred: image.Pt(w, h),
+		Min:       image.Pt(w, h),
+		Max:       image.Pt(guigui.Unbounded, guigui.Unbounded),
+	}
+}
+
+func (g *Grid) Size(context *guigui.Context) (int, int) {
+	if g.sizeSet {
+		return g.width, g.height
+	}
+	m := guigui.Measure(context, g, guigui.UnboundedConstraints())
+	return m.Preferred.X, m.Preferred.Y
+}
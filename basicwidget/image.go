@@ -38,6 +38,17 @@ func (i *Image) Draw(context *guigui.Context, dst *ebiten.Image) {
 	dst.DrawImage(i.image, op)
 }
 
+func (i *Image) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role: guigui.AccessibilityRoleImage,
+		Leaf: true,
+	}
+}
+
+func (i *Image) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	return false
+}
+
 func (i *Image) HasImage() bool {
 	return i.image != nil
 }
@@ -61,6 +72,10 @@ func (i *Image) Size(context *guigui.Context) (int, int) {
 
 func (i *Image) SetSize(context *guigui.Context, width, height int) {
 	dw, dh := defaultImageSize(context)
+	if i.widthMinusDefault == width-dw && i.heightMinusDefault == height-dh {
+		return
+	}
 	i.widthMinusDefault = width - dw
 	i.heightMinusDefault = height - dh
+	guigui.RequestRelayout(i)
 }
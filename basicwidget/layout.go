@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"math"
+
+	"github.com/xackery/guigui"
+)
+
+// LayoutDirection is the direction in which a layout container arranges its widgets.
+type LayoutDirection int
+
+const (
+	LayoutDirectionVertical LayoutDirection = iota
+	LayoutDirectionHorizontal
+)
+
+// LayoutAlignment is the alignment of a widget in the space given by a layout container.
+type LayoutAlignment int
+
+const (
+	LayoutAlignmentStart LayoutAlignment = iota
+	LayoutAlignmentCenter
+	LayoutAlignmentEnd
+
+	// LayoutAlignmentStretch resizes the widget to fill the space.
+	// A widget is resized only when it has a SetSize or SetWidth method.
+	LayoutAlignmentStretch
+)
+
+func (l LayoutAlignment) align(space, size int) (int, int) {
+	switch l {
+	case LayoutAlignmentCenter:
+		return (space - size) / 2, size
+	case LayoutAlignmentEnd:
+		return space - size, size
+	case LayoutAlignmentStretch:
+		return 0, space
+	}
+	return 0, size
+}
+
+// setWidgetSize sets the size of the widget by its SetSize or SetWidth method if exists.
+// setWidgetSize reports whether the widget is resizable.
+func setWidgetSize(context *guigui.Context, widget guigui.Widget, width, height int) bool {
+	switch w := widget.(type) {
+	case interface {
+		SetSize(context *guigui.Context, width, height int)
+	}:
+		w.SetSize(context, width, height)
+	case interface{ SetSize(width, height int) }:
+		w.SetSize(width, height)
+	case interface {
+		SetWidth(context *guigui.Context, width int)
+	}:
+		w.SetWidth(context, width)
+	case interface{ SetWidth(width int) }:
+		w.SetWidth(width)
+	default:
+		return false
+	}
+	return true
+}
+
+type layoutSize struct {
+	natural  image.Point
+	assigned image.Point
+}
+
+// layoutSizer measures and resizes the widgets in a layout container.
+//
+// A widget implementing guigui.Measurer reports its sizes by the content.
+// For other widgets, once a widget is resized, Size returns the assigned size instead of the size by its content.
+// layoutSizer keeps the size before the resize so that the widget can be shrunk again when the container gets smaller.
+type layoutSizer struct {
+	sizes     map[guigui.Widget]layoutSize
+	prevSizes map[guigui.Widget]layoutSize
+}
+
+// reset starts a new layout pass. The sizes of the widgets not resized in the pass are forgotten.
+func (l *layoutSizer) reset() {
+	l.prevSizes, l.sizes = l.sizes, l.prevSizes
+	clear(l.sizes)
+}
+
+func (l *layoutSizer) measure(context *guigui.Context, widget guigui.Widget, constraints guigui.Constraints) guigui.Measurement {
+	m := guigui.Measure(context, widget, constraints)
+	if _, ok := widget.(guigui.Measurer); ok {
+		return m
+	}
+	m.Preferred.X, m.Preferred.Y = constraints.Constrain(l.naturalSize(context, widget))
+	return m
+}
+
+func (l *layoutSizer) naturalSize(context *guigui.Context, widget guigui.Widget) (int, int) {
+	w, h := widget.Size(context)
+	if s, ok := l.sizes[widget]; ok && s.assigned == image.Pt(w, h) {
+		return s.natural.X, s.natural.Y
+	}
+	if s, ok := l.prevSizes[widget]; ok && s.assigned == image.Pt(w, h) {
+		return s.natural.X, s.natural.Y
+	}
+	return w, h
+}
+
+// setSize resizes the widget and returns the actual size of the widget.
+func (l *layoutSizer) setSize(context *guigui.Context, widget guigui.Widget, width, height int) (int, int) {
+	nw, nh := l.naturalSize(context, widget)
+	if w, h := widget.Size(context); w == width && h == height {
+		if w != nw || h != nh {
+			l.store(widget, image.Pt(nw, nh), image.Pt(w, h))
+		}
+		return w, h
+	}
+	if !setWidgetSize(context, widget, width, height) {
+		return widget.Size(context)
+	}
+	w, h := widget.Size(context)
+	l.store(widget, image.Pt(nw, nh), image.Pt(w, h))
+	return w, h
+}
+
+func (l *layoutSizer) store(widget guigui.Widget, natural, assigned image.Point) {
+	if l.sizes == nil {
+		l.sizes = map[guigui.Widget]layoutSize{}
+	}
+	l.sizes[widget] = layoutSize{
+		natural:  natural,
+		assigned: assigned,
+	}
+}
+
+// distribute distributes total to the items by the weights.
+// The results are rounded so that the sum of the results equals to total.
+func distribute(total int, weights []float64, results []int) {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		return
+	}
+	var acc float64
+	var prev int
+	for i, w := range weights {
+		acc += w
+		next := int(math.Round(float64(total) * acc / sum))
+		results[i] += next - prev
+		prev = next
+	}
+}
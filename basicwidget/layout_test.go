@@ -0,0 +1,422 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/xackery/guigui"
+)
+
+// sizedWidget is a widget with a fixed size that can be resized by a layout container.
+type sizedWidget struct {
+	guigui.DefaultWidget
+
+	width  int
+	height int
+}
+
+func (s *sizedWidget) SetSize(context *guigui.Context, width, height int) {
+	s.width = width
+	s.height = height
+}
+
+func (s *sizedWidget) Size(context *guigui.Context) (int, int) {
+	return s.width, s.height
+}
+
+func TestDistribute(t *testing.T) {
+	testCases := []struct {
+		total   int
+		weights []float64
+		results []int
+		want    []int
+	}{
+		{total: 10, weights: []float64{1, 1}, results: []int{0, 0}, want: []int{5, 5}},
+		{total: 10, weights: []float64{1, 1, 1}, results: []int{0, 0, 0}, want: []int{3, 4, 3}},
+		{total: 10, weights: []float64{1, 3}, results: []int{0, 0}, want: []int{3, 7}},
+		{total: 10, weights: []float64{0, 1}, results: []int{0, 0}, want: []int{0, 10}},
+		{total: 10, weights: []float64{0, 0}, results: []int{0, 0}, want: []int{0, 0}},
+		{total: 3, weights: []float64{1, 0}, results: []int{5, 5}, want: []int{8, 5}},
+		{total: -6, weights: []float64{1, 2}, results: []int{10, 10}, want: []int{8, 6}},
+		{total: 0, weights: []float64{1, 1}, results: []int{4, 4}, want: []int{4, 4}},
+		{total: 10, weights: nil, results: nil, want: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d/%v/%v", tc.total, tc.weights, tc.results), func(t *testing.T) {
+			got := slices.Clone(tc.results)
+			distribute(tc.total, tc.weights, got)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got: %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLayoutAlignment(t *testing.T) {
+	testCases := []struct {
+		alignment  LayoutAlignment
+		space      int
+		size       int
+		wantOffset int
+		wantSize   int
+	}{
+		{alignment: LayoutAlignmentStart, space: 100, size: 30, wantOffset: 0, wantSize: 30},
+		{alignment: LayoutAlignmentCenter, space: 100, size: 30, wantOffset: 35, wantSize: 30},
+		{alignment: LayoutAlignmentEnd, space: 100, size: 30, wantOffset: 70, wantSize: 30},
+		{alignment: LayoutAlignmentStretch, space: 100, size: 30, wantOffset: 0, wantSize: 100},
+		{alignment: LayoutAlignmentCenter, space: 0, size: 0, wantOffset: 0, wantSize: 0},
+		{alignment: LayoutAlignmentStretch, space: 0, size: 30, wantOffset: 0, wantSize: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d/%d/%d", tc.alignment, tc.space, tc.size), func(t *testing.T) {
+			offset, size := tc.alignment.align(tc.space, tc.size)
+			if offset != tc.wantOffset || size != tc.wantSize {
+				t.Errorf("got: (%d, %d), want: (%d, %d)", offset, size, tc.wantOffset, tc.wantSize)
+			}
+		})
+	}
+}
+
+type testFlexItem struct {
+	width  int
+	height int
+	grow   float64
+	shrink float64
+}
+
+func TestFlexLayout(t *testing.T) {
+	testCases := []struct {
+		name      string
+		direction LayoutDirection
+		gap       int
+		width     int
+		height    int
+		items     []testFlexItem
+		want      []image.Rectangle
+	}{
+		{
+			name:      "fixed",
+			direction: LayoutDirectionHorizontal,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 20, height: 10}, {width: 30, height: 10}},
+			want:      []image.Rectangle{image.Rect(0, 0, 20, 10), image.Rect(20, 0, 50, 10)},
+		},
+		{
+			name:      "grow",
+			direction: LayoutDirectionHorizontal,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 20, height: 10}, {width: 30, height: 10, grow: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 20, 10), image.Rect(20, 0, 100, 10)},
+		},
+		{
+			name:      "grow weights",
+			direction: LayoutDirectionHorizontal,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 10, height: 10, grow: 1}, {width: 10, height: 10, grow: 3}},
+			want:      []image.Rectangle{image.Rect(0, 0, 30, 10), image.Rect(30, 0, 100, 10)},
+		},
+		{
+			name:      "gap",
+			direction: LayoutDirectionHorizontal,
+			gap:       10,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 20, height: 10}, {width: 20, height: 10, grow: 1}, {width: 20, height: 10}},
+			want:      []image.Rectangle{image.Rect(0, 0, 20, 10), image.Rect(30, 0, 70, 10), image.Rect(80, 0, 100, 10)},
+		},
+		{
+			name:      "vertical gap",
+			direction: LayoutDirectionVertical,
+			gap:       5,
+			width:     20,
+			height:    100,
+			items:     []testFlexItem{{width: 10, height: 20}, {width: 10, height: 20, grow: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 10, 20), image.Rect(0, 25, 10, 100)},
+		},
+		{
+			name:      "shrink",
+			direction: LayoutDirectionHorizontal,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 80, height: 10, shrink: 1}, {width: 80, height: 10, shrink: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 50, 10), image.Rect(50, 0, 100, 10)},
+		},
+		{
+			name:      "shrink and fixed",
+			direction: LayoutDirectionHorizontal,
+			width:     100,
+			height:    20,
+			items:     []testFlexItem{{width: 80, height: 10}, {width: 80, height: 10, shrink: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 80, 10), image.Rect(80, 0, 100, 10)},
+		},
+		{
+			name:      "zero size",
+			direction: LayoutDirectionHorizontal,
+			items:     []testFlexItem{{width: 20, height: 10, shrink: 1}, {width: 30, height: 10, shrink: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 0, 0), image.Rect(0, 0, 0, 0)},
+		},
+		{
+			name:      "zero size without shrink",
+			direction: LayoutDirectionHorizontal,
+			gap:       10,
+			items:     []testFlexItem{{width: 20, height: 10}, {width: 30, height: 10, grow: 1}},
+			want:      []image.Rectangle{image.Rect(0, 0, 20, 0), image.Rect(30, 0, 60, 0)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var flex Flex
+			var items []FlexItem
+			var widgets []*sizedWidget
+			for _, item := range tc.items {
+				w := &sizedWidget{width: item.width, height: item.height}
+				widgets = append(widgets, w)
+				items = append(items, FlexItem{
+					Widget: w,
+					Grow:   item.grow,
+					Shrink: item.shrink,
+				})
+			}
+			flex.SetItems(items)
+			flex.SetDirection(tc.direction)
+			flex.SetGap(tc.gap)
+
+			d := newTestDriver(&flex)
+			flex.SetSize(d.Context(), tc.width, tc.height)
+			if err := d.Step(); err != nil {
+				t.Fatal(err)
+			}
+			for i, w := range widgets {
+				if got := guigui.Bounds(w); got != tc.want[i] {
+					t.Errorf("item %d: got: %v, want: %v", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStackLayout(t *testing.T) {
+	testCases := []struct {
+		name      string
+		direction LayoutDirection
+		gap       int
+		alignment LayoutAlignment
+		sizeSet   bool
+		width     int
+		height    int
+		items     []image.Point
+		want      []image.Rectangle
+		wantSize  image.Point
+	}{
+		{
+			name:     "vertical gap",
+			gap:      4,
+			items:    []image.Point{{20, 10}, {30, 10}},
+			want:     []image.Rectangle{image.Rect(0, 0, 20, 10), image.Rect(0, 14, 30, 24)},
+			wantSize: image.Pt(30, 24),
+		},
+		{
+			name:      "horizontal gap",
+			direction: LayoutDirectionHorizontal,
+			gap:       4,
+			items:     []image.Point{{20, 10}, {30, 5}},
+			want:      []image.Rectangle{image.Rect(0, 0, 20, 10), image.Rect(24, 0, 54, 5)},
+			wantSize:  image.Pt(54, 10),
+		},
+		{
+			name:      "center",
+			alignment: LayoutAlignmentCenter,
+			sizeSet:   true,
+			width:     40,
+			height:    100,
+			items:     []image.Point{{20, 10}, {30, 10}},
+			want:      []image.Rectangle{image.Rect(10, 0, 30, 10), image.Rect(5, 10, 35, 20)},
+			wantSize:  image.Pt(40, 100),
+		},
+		{
+			name:      "stretch",
+			alignment: LayoutAlignmentStretch,
+			sizeSet:   true,
+			width:     40,
+			height:    100,
+			items:     []image.Point{{20, 10}, {30, 10}},
+			want:      []image.Rectangle{image.Rect(0, 0, 40, 10), image.Rect(0, 10, 40, 20)},
+			wantSize:  image.Pt(40, 100),
+		},
+		{
+			name:     "no widgets",
+			gap:      4,
+			wantSize: image.Pt(0, 0),
+		},
+		{
+			name:     "zero-size widgets",
+			gap:      4,
+			items:    []image.Point{{0, 0}, {0, 0}},
+			want:     []image.Rectangle{image.Rect(0, 0, 0, 0), image.Rect(0, 4, 0, 4)},
+			wantSize: image.Pt(0, 4),
+		},
+		{
+			name:     "zero size",
+			gap:      4,
+			sizeSet:  true,
+			items:    []image.Point{{20, 10}, {30, 10}},
+			want:     []image.Rectangle{image.Rect(0, 0, 0, 10), image.Rect(0, 14, 0, 24)},
+			wantSize: image.Pt(0, 0),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stack Stack
+			var widgets []guigui.Widget
+			for _, item := range tc.items {
+				widgets = append(widgets, &sizedWidget{width: item.X, height: item.Y})
+			}
+			stack.SetWidgets(widgets)
+			stack.SetDirection(tc.direction)
+			stack.SetGap(tc.gap)
+			stack.SetAlignment(tc.alignment)
+
+			d := newTestDriver(&stack)
+			if tc.sizeSet {
+				stack.SetSize(d.Context(), tc.width, tc.height)
+			}
+			if err := d.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if w, h := stack.Size(d.Context()); image.Pt(w, h) != tc.wantSize {
+				t.Errorf("size: got: (%d, %d), want: %v", w, h, tc.wantSize)
+			}
+			for i, w := range widgets {
+				if got := guigui.Bounds(w); got != tc.want[i] {
+					t.Errorf("item %d: got: %v, want: %v", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGridCalcTracks(t *testing.T) {
+	type cell struct {
+		column  int
+		span    int
+		natural int
+	}
+	testCases := []struct {
+		name   string
+		tracks []GridTrack
+		count  int
+		gap    int
+		space  int
+		cells  []cell
+		want   []int
+	}{
+		{
+			name:   "fixed and fractions",
+			tracks: []GridTrack{{Type: GridTrackTypeFixed, Size: 20}, {Type: GridTrackTypeFraction, Fraction: 1}, {Type: GridTrackTypeFraction, Fraction: 2}},
+			count:  3,
+			gap:    5,
+			space:  100,
+			want:   []int{20, 23, 47},
+		},
+		{
+			name:   "auto",
+			tracks: []GridTrack{{Type: GridTrackTypeAuto}, {Type: GridTrackTypeFraction, Fraction: 1}},
+			count:  2,
+			space:  100,
+			cells:  []cell{{column: 0, natural: 30}, {column: 1, natural: 10}},
+			want:   []int{30, 70},
+		},
+		{
+			name:   "spanning",
+			tracks: []GridTrack{{Type: GridTrackTypeAuto}, {Type: GridTrackTypeAuto}},
+			count:  2,
+			gap:    10,
+			space:  100,
+			cells:  []cell{{column: 0, span: 2, natural: 50}},
+			want:   []int{20, 20},
+		},
+		{
+			name:   "spanning after single cells",
+			tracks: []GridTrack{{Type: GridTrackTypeAuto}, {Type: GridTrackTypeAuto}},
+			count:  2,
+			space:  100,
+			cells:  []cell{{column: 0, span: 2, natural: 30}, {column: 0, natural: 10}},
+			want:   []int{20, 10},
+		},
+		{
+			name:   "spanning fixed",
+			tracks: []GridTrack{{Type: GridTrackTypeFixed, Size: 20}, {Type: GridTrackTypeAuto}},
+			count:  2,
+			gap:    5,
+			space:  100,
+			cells:  []cell{{column: 0, span: 2, natural: 40}},
+			want:   []int{20, 15},
+		},
+		{
+			name:   "natural fractions",
+			tracks: []GridTrack{{Type: GridTrackTypeFraction, Fraction: 1}, {Type: GridTrackTypeFraction, Fraction: 2}},
+			count:  2,
+			gap:    5,
+			space:  -1,
+			cells:  []cell{{column: 0, natural: 15}, {column: 1, natural: 25}},
+			want:   []int{15, 25},
+		},
+		{
+			name:   "implicit tracks",
+			tracks: []GridTrack{{Type: GridTrackTypeFixed, Size: 10}},
+			count:  3,
+			space:  100,
+			cells:  []cell{{column: 2, natural: 15}},
+			want:   []int{10, 0, 15},
+		},
+		{
+			name:   "overflow",
+			tracks: []GridTrack{{Type: GridTrackTypeAuto}, {Type: GridTrackTypeFraction, Fraction: 1}},
+			count:  2,
+			space:  20,
+			cells:  []cell{{column: 0, natural: 30}},
+			want:   []int{30, 0},
+		},
+		{
+			name:   "zero space",
+			tracks: []GridTrack{{Type: GridTrackTypeFixed, Size: 20}, {Type: GridTrackTypeFraction, Fraction: 1}},
+			count:  2,
+			gap:    5,
+			space:  0,
+			want:   []int{20, 0},
+		},
+		{
+			name:  "no tracks",
+			space: 100,
+			want:  nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var g Grid
+			var naturalSizes []int
+			for _, c := range tc.cells {
+				g.items = append(g.items, GridItem{
+					Widget:     &sizedWidget{},
+					Column:     c.column,
+					ColumnSpan: c.span,
+				})
+				naturalSizes = append(naturalSizes, c.natural)
+			}
+			got := g.calcTracks(nil, tc.tracks, tc.count, tc.gap, tc.space, func(item *GridItem) (int, int) {
+				return item.Column, item.columnSpan()
+			}, naturalSizes)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got: %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/xackery/guigui"
@@ -46,6 +45,7 @@ type List struct {
 	listFrame       listFrame
 	scrollOverlay   ScrollOverlay
 	dragDropOverlay DragDropOverlay
+	focusRing       focusRing
 
 	items                  []ListItem
 	selectedItemIndexPlus1 int
@@ -61,6 +61,8 @@ type List struct {
 	pressStartY             int
 	startPressingIndexPlus1 int
 	startPressingLeft       bool
+	lastCursorPosition      image.Point
+	lastCursorPositionSet   bool
 
 	widthSet            bool
 	heightSet           bool
@@ -69,7 +71,10 @@ type List struct {
 	cachedDefaultWidth  int
 	cachedDefaultHeight int
 
+	contextMenu *contextMenu
+
 	onItemSelected func(index int)
+	onContextMenu  func(index int, position image.Point) []ContextMenuItem
 }
 
 func listItemPadding(context *guigui.Context) int {
@@ -80,6 +85,24 @@ func (l *List) SetOnItemSelected(f func(index int)) {
 	l.onItemSelected = f
 }
 
+// SetOnContextMenu sets the function to return the items of the context menu.
+// The function is called when the list is right-clicked, or the Menu key or Shift+F10 is pressed while the list is focused.
+// index is the index of the item for the menu, or -1 if the menu is not for an item.
+// position is the position to open the menu.
+func (l *List) SetOnContextMenu(f func(index int, position image.Point) []ContextMenuItem) {
+	l.onContextMenu = f
+}
+
+func (l *List) openContextMenu(context *guigui.Context, index int, position image.Point) bool {
+	if l.onContextMenu == nil {
+		return false
+	}
+	if l.contextMenu == nil {
+		l.contextMenu = &contextMenu{}
+	}
+	return l.contextMenu.open(context, position, l.onContextMenu(index, position))
+}
+
 func (l *List) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
 	if l.style != ListStyleSidebar && l.style != ListStyleMenu {
 		guigui.SetPosition(&l.listFrame, guigui.Position(l))
@@ -108,6 +131,14 @@ func (l *List) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppen
 	appender.AppendChildWidget(&l.scrollOverlay)
 	guigui.SetPosition(&l.dragDropOverlay, p)
 	appender.AppendChildWidget(&l.dragDropOverlay)
+
+	if l.contextMenu != nil {
+		appender.AppendChildWidget(&l.contextMenu.popupMenu)
+	}
+
+	// A menu is operated by the keys via PopupMenu.
+	guigui.SetFocusable(l, l.style != ListStyleMenu)
+	appendFocusRing(context, appender, &l.focusRing, l)
 }
 
 func (l *List) SelectedItem() (ListItem, bool) {
@@ -134,28 +165,55 @@ func (l *List) HoveredItemIndex() int {
 }
 
 func (l *List) SetItems(items []ListItem) {
+	changed := !slices.EqualFunc(l.items, items, isSameListItemLayout)
 	l.items = make([]ListItem, len(items))
 	copy(l.items, items)
+	// The contents' sizes might be changed even when the contents are the same.
 	l.cachedDefaultWidth = 0
 	l.cachedDefaultHeight = 0
+	if changed {
+		l.itemsChanged()
+	}
 }
 
 func (l *List) SetItem(item ListItem, index int) {
+	changed := !isSameListItemLayout(l.items[index], item)
 	l.items[index] = item
+	l.cachedDefaultWidth = 0
+	l.cachedDefaultHeight = 0
+	if changed {
+		l.itemsChanged()
+	}
+}
+
+// isSameListItemLayout reports whether the two items are laid out and drawn in the same way.
+// Tag is not compared, as Tag doesn't affect the layout and might not be comparable.
+func isSameListItemLayout(a, b ListItem) bool {
+	return a.Content == b.Content && a.Selectable == b.Selectable && a.Wide == b.Wide && a.Draggable == b.Draggable
+}
+
+func (l *List) itemsChanged() {
+	l.cachedDefaultWidth = 0
+	l.cachedDefaultHeight = 0
+	guigui.RequestRedraw(l)
+	guigui.RequestRelayout(l)
 }
 
 func (l *List) AddItem(item ListItem, index int) {
 	l.items = slices.Insert(l.items, index, item)
+	l.itemsChanged()
 	// TODO: Send an event.
 }
 
 func (l *List) RemoveItem(index int) {
 	l.items = slices.Delete(l.items, index, index+1)
+	l.itemsChanged()
 	// TODO: Send an event.
 }
 
 func (l *List) MoveItem(from int, to int) {
 	moveItemInSlice(l.items, from, 1, to)
+	l.itemsChanged()
 	// TODO: Send an event.
 }
 
@@ -179,6 +237,18 @@ func (l *List) JumpToItemIndex(index int) {
 	l.indexToJumpPlus1 = index + 1
 }
 
+// cursorMoved reports whether the cursor has moved since the last input handling.
+// The first position after resetCursorPosition is not regarded as a move.
+func (l *List) cursorMoved(cursorPosition image.Point) bool {
+	return l.lastCursorPositionSet && cursorPosition != l.lastCursorPosition
+}
+
+// resetCursorPosition forgets the last cursor position.
+// This is used when a menu is opened so that an item highlighted by keys is kept until the cursor moves.
+func (l *List) resetCursorPosition() {
+	l.lastCursorPositionSet = false
+}
+
 func (l *List) setHoveredItemIndex(index int) {
 	if index < 0 || index >= len(l.items) {
 		index = -1
@@ -200,6 +270,20 @@ func (l *List) ShowItemBorders(show bool) {
 	guigui.RequestRedraw(l)
 }
 
+func (l *List) Accessibility(context *guigui.Context) guigui.Accessibility {
+	role := guigui.AccessibilityRoleList
+	if l.style == ListStyleMenu {
+		role = guigui.AccessibilityRoleMenu
+	}
+	return guigui.Accessibility{
+		Role: role,
+	}
+}
+
+func (l *List) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	return false
+}
+
 func (l *List) isHoveringVisible() bool {
 	return l.style == ListStyleMenu
 }
@@ -214,10 +298,12 @@ func (l *List) SetStyle(style ListStyle) {
 	}
 	l.style = style
 	guigui.RequestRedraw(l)
+	// The frame and the focusability depend on the style.
+	guigui.RequestRelayout(l)
 }
 
 func (l *List) calcDropDstIndex(context *guigui.Context) int {
-	_, y := ebiten.CursorPosition()
+	y := context.Input().CursorPosition().Y
 	for i := range l.items {
 		if r := l.itemRect(context, i); y < (r.Min.Y+r.Max.Y)/2 {
 			return i
@@ -227,9 +313,11 @@ func (l *List) calcDropDstIndex(context *guigui.Context) int {
 }
 
 func (l *List) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	input := context.Input()
+
 	// Process dragging.
 	if l.dragDropOverlay.IsDragging() {
-		_, y := ebiten.CursorPosition()
+		y := input.CursorPosition().Y
 		p := guigui.Position(l)
 		_, h := l.Size(context)
 		var dy float64
@@ -267,7 +355,25 @@ func (l *List) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 		return guigui.HandleInputByWidget(l)
 	}
 
-	if x, y := ebiten.CursorPosition(); image.Pt(x, y).In(guigui.VisibleBounds(l)) {
+	if guigui.IsFocused(l) && isContextMenuKeyJustPressed(input) {
+		// Open the menu below the selected item.
+		index := l.SelectedItemIndex()
+		position := guigui.Position(l)
+		if index >= 0 && index < len(l.items) {
+			r := l.itemRect(context, index)
+			position = image.Pt(r.Min.X, r.Max.Y)
+		}
+		if l.openContextMenu(context, index, position) {
+			return guigui.HandleInputByWidget(l)
+		}
+	}
+
+	if guigui.IsFocused(l) && l.style != ListStyleMenu && l.handleKeyInput(context) {
+		return guigui.HandleInputByWidget(l)
+	}
+
+	if cp := input.CursorPosition(); cp.In(guigui.VisibleBounds(l)) {
+		x, y := cp.X, cp.Y
 		_, offsetY := l.scrollOverlay.Offset()
 		y -= RoundedCornerRadius(context)
 		y -= guigui.Position(l).Y
@@ -282,10 +388,15 @@ func (l *List) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 			}
 			cy += h
 		}
-		l.setHoveredItemIndex(index)
+		// In a menu, keep the hovered item while the cursor doesn't move so that the item can be chosen by keys.
+		if l.style != ListStyleMenu || l.cursorMoved(cp) {
+			l.setHoveredItemIndex(index)
+		}
+		l.lastCursorPosition = cp
+		l.lastCursorPositionSet = true
 		if index >= 0 && index < len(l.items) {
-			left := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
-			right := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+			left := input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+			right := input.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
 
 			switch {
 			case left || right:
@@ -302,20 +413,17 @@ func (l *List) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				l.pressStartX = x
 				l.pressStartY = y
 				if right {
-					/*if l.callback != nil && l.callback.OnContextMenu != nil {
-						x, y := ebiten.CursorPosition()
-						l.callback.OnContextMenu(index, x, y)
-					}*/
+					l.openContextMenu(context, index, cp)
 				}
 				l.startPressingIndexPlus1 = index + 1
 				l.startPressingLeft = left
 
-			case ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft):
+			case input.IsMouseButtonPressed(ebiten.MouseButtonLeft):
 				if l.items[index].Draggable && l.SelectedItemIndex() == index && l.startPressingIndexPlus1-1 == index && (l.pressStartX != x || l.pressStartY != y) {
 					l.dragDropOverlay.Start(index)
 				}
 
-			case inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
+			case input.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
 				if l.SelectedItemIndex() == index && l.startPressingLeft && time.Since(l.lastSelectingItemTime) > 400*time.Millisecond {
 					/*if l.callback != nil && l.callback.OnItemEditStarted != nil {
 						l.callback.OnItemEditStarted(index)
@@ -332,13 +440,61 @@ func (l *List) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 		l.dropSrcIndexPlus1 = 0
 		l.pressStartX = 0
 		l.pressStartY = 0
+		if input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && l.openContextMenu(context, -1, cp) {
+			return guigui.HandleInputByWidget(l)
+		}
 	} else {
-		l.setHoveredItemIndex(-1)
+		if l.style != ListStyleMenu || l.cursorMoved(cp) {
+			l.setHoveredItemIndex(-1)
+		}
+		l.lastCursorPosition = cp
+		l.lastCursorPositionSet = true
 	}
 
 	return guigui.HandleInputResult{}
 }
 
+// handleKeyInput selects an item by the keys. Up and Down move the selection, and Space and Enter select the current item again.
+func (l *List) handleKeyInput(context *guigui.Context) bool {
+	input := context.Input()
+	idx := l.SelectedItemIndex()
+	switch {
+	case isKeyRepeating(input, ebiten.KeyDown):
+		idx = l.nextSelectableItemIndex(idx, 1)
+	case isKeyRepeating(input, ebiten.KeyUp):
+		idx = l.nextSelectableItemIndex(idx, -1)
+	case isActivationKeyJustPressed(input):
+		if idx < 0 {
+			idx = l.nextSelectableItemIndex(-1, 1)
+		}
+	default:
+		return false
+	}
+	if idx < 0 {
+		return true
+	}
+	l.SetSelectedItemIndex(idx)
+	if !l.itemRect(context, idx).In(guigui.VisibleBounds(l)) {
+		l.JumpToItemIndex(idx)
+	}
+	return true
+}
+
+// nextSelectableItemIndex returns the index of the next selectable item from the index in the direction.
+// If from is negative, the search starts from the first item for a positive direction, or from the last item for a negative direction.
+// If there is no selectable item, nextSelectableItemIndex returns -1.
+func (l *List) nextSelectableItemIndex(from int, direction int) int {
+	if from < 0 && direction < 0 {
+		from = len(l.items)
+	}
+	for i := from + direction; i >= 0 && i < len(l.items); i += direction {
+		if l.items[i].Selectable {
+			return i
+		}
+	}
+	return -1
+}
+
 func (l *List) Update(context *guigui.Context) error {
 	w, _ := l.Size(context)
 	l.scrollOverlay.SetContentSize(w, l.defaultHeight(context))
@@ -531,30 +687,44 @@ func (l *List) Size(context *guigui.Context) (int, int) {
 }
 
 func (l *List) SetSize(width, height int) {
-	l.width = width
-	l.widthSet = true
-	l.height = height
-	l.heightSet = true
+	l.SetWidth(width)
+	l.SetHeight(height)
 }
 
 func (l *List) SetWidth(width int) {
+	if l.widthSet && l.width == width {
+		return
+	}
 	l.width = width
 	l.widthSet = true
+	guigui.RequestRelayout(l)
 }
 
 func (l *List) SetHeight(height int) {
+	if l.heightSet && l.height == height {
+		return
+	}
 	l.height = height
 	l.heightSet = true
+	guigui.RequestRelayout(l)
 }
 
 func (l *List) ResetWidth() {
+	if !l.widthSet {
+		return
+	}
 	l.widthSet = false
 	l.width = 0
+	guigui.RequestRelayout(l)
 }
 
 func (l *List) ResetHeight() {
+	if !l.heightSet {
+		return
+	}
 	l.heightSet = false
 	l.height = 0
+	guigui.RequestRelayout(l)
 }
 
 type listFrame struct {
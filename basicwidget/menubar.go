@@ -0,0 +1,491 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/xackery/guigui"
+)
+
+// MenuBarMenu is a menu of a menu bar.
+type MenuBarMenu struct {
+	// Title is the title of the menu.
+	// The character after '&' is the mnemonic, like "&File". "&&" is shown as '&'.
+	Title string
+
+	Items []PopupMenuItem
+}
+
+// parseMenuTitle returns the title to show and the index of the mnemonic in bytes in the title.
+// If the title has no mnemonic, the index is -1.
+func parseMenuTitle(title string) (string, int) {
+	var sb strings.Builder
+	mnemonicIndex := -1
+	for i := 0; i < len(title); i++ {
+		if title[i] != '&' || i == len(title)-1 {
+			sb.WriteByte(title[i])
+			continue
+		}
+		i++
+		if title[i] != '&' && mnemonicIndex < 0 {
+			mnemonicIndex = sb.Len()
+		}
+		sb.WriteByte(title[i])
+	}
+	return sb.String(), mnemonicIndex
+}
+
+// mnemonicKey returns the key of the mnemonic of the title.
+// Only an alphabet or a digit can be a mnemonic.
+func mnemonicKey(title string) (ebiten.Key, bool) {
+	str, idx := parseMenuTitle(title)
+	if idx < 0 {
+		return 0, false
+	}
+	r, _ := utf8.DecodeRuneInString(str[idx:])
+	r = unicode.ToLower(r)
+	switch {
+	case 'a' <= r && r <= 'z':
+		return ebiten.KeyA + ebiten.Key(r-'a'), true
+	case '0' <= r && r <= '9':
+		return ebiten.KeyDigit0 + ebiten.Key(r-'0'), true
+	}
+	return 0, false
+}
+
+// MenuBar is a bar of menus for a desktop-style application.
+//
+// A menu is opened by clicking the title, or pressing Alt and the mnemonic of the title.
+// Pressing and releasing Alt alone highlights the first title, and then the titles can be chosen by keys.
+// While a menu is open, Left and Right move to the adjacent menu, and hovering over another title opens the menu.
+//
+// The shortcut texts of the items are taken from the key bindings of the items' commands.
+// The checked states of the items are not kept by the menu bar. Update the items by SetMenus to change the states.
+type MenuBar struct {
+	guigui.DefaultWidget
+
+	titles    []*menuBarTitle
+	popupMenu PopupMenu
+
+	menus []MenuBarMenu
+
+	openMenuIndexPlus1        int
+	highlightedMenuIndexPlus1 int
+	hoveredMenuIndexPlus1     int
+	openedByKey               bool
+	altAlone                  bool
+	lastCursorPosition        image.Point
+
+	focusSaved    bool
+	focusedWidget guigui.Widget
+
+	widthSet bool
+	width    int
+
+	onItemSelected func(id string)
+}
+
+// SetMenus sets the menus.
+func (m *MenuBar) SetMenus(menus []MenuBarMenu) {
+	if slices.EqualFunc(m.menus, menus, func(menu0, menu1 MenuBarMenu) bool {
+		return menu0.Title == menu1.Title && equalPopupMenuItems(menu0.Items, menu1.Items)
+	}) {
+		return
+	}
+	m.menus = append(m.menus[:0], menus...)
+	// Copy the items so that modifying the given items is detected at the next call.
+	for i := range m.menus {
+		m.menus[i].Items = clonePopupMenuItems(m.menus[i].Items)
+	}
+
+	if cap(m.titles) < len(menus) {
+		m.titles = append(m.titles, make([]*menuBarTitle, len(menus)-cap(m.titles))...)
+	}
+	m.titles = m.titles[:len(menus)]
+	for i := range m.titles {
+		if m.titles[i] == nil {
+			m.titles[i] = &menuBarTitle{}
+		}
+		m.titles[i].menuBar = m
+		m.titles[i].index = i
+	}
+
+	if idx := m.openMenuIndexPlus1 - 1; idx >= len(menus) {
+		m.popupMenu.Close()
+	} else if idx >= 0 {
+		m.popupMenu.SetItems(menus[idx].Items)
+	}
+	if m.highlightedMenuIndexPlus1-1 >= len(menus) {
+		m.highlightedMenuIndexPlus1 = 0
+	}
+	guigui.RequestRelayout(m)
+}
+
+// SetOnItemSelected sets the function called when an item of a menu is chosen.
+// id is the ID of the chosen item. The function is called after the item's command is executed.
+func (m *MenuBar) SetOnItemSelected(f func(id string)) {
+	m.onItemSelected = f
+}
+
+func (m *MenuBar) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	p := guigui.Position(m)
+	for _, t := range m.titles {
+		t.updateText(context)
+		guigui.SetPosition(t, p)
+		appender.AppendChildWidget(t)
+		w, _ := t.Size(context)
+		p.X += w
+	}
+
+	m.popupMenu.onAdjacentMenu = func(direction int) {
+		n := len(m.menus)
+		m.openMenu(context, ((m.openMenuIndexPlus1-1+direction)%n+n)%n, true)
+	}
+	m.popupMenu.onClose = func() {
+		m.openMenuIndexPlus1 = 0
+		m.restoreFocus(context)
+	}
+	m.popupMenu.SetOnItemSelected(func(id string) {
+		if m.onItemSelected != nil {
+			m.onItemSelected(id)
+		}
+	})
+	appender.AppendChildWidget(&m.popupMenu)
+}
+
+func (m *MenuBar) openMenu(context *guigui.Context, index int, byKey bool) {
+	if index < 0 || index >= len(m.menus) {
+		return
+	}
+	m.saveFocus(context)
+	m.popupMenu.closeSubmenu()
+	m.openMenuIndexPlus1 = index + 1
+	m.highlightedMenuIndexPlus1 = 0
+	m.openedByKey = byKey
+	m.popupMenu.SetItems(m.menus[index].Items)
+	guigui.SetPosition(&m.popupMenu, image.Pt(guigui.Position(m.titles[index]).X, guigui.Bounds(m).Max.Y))
+	m.popupMenu.open(byKey)
+	guigui.RequestRedraw(m)
+}
+
+// saveFocus saves the focused widget to restore it after the menu is closed,
+// as the menu takes the focus while the menu is operated.
+func (m *MenuBar) saveFocus(context *guigui.Context) {
+	if m.focusSaved {
+		return
+	}
+	m.focusedWidget = context.FocusedWidget()
+	m.focusSaved = true
+}
+
+func (m *MenuBar) restoreFocus(context *guigui.Context) {
+	if !m.focusSaved {
+		return
+	}
+	if m.focusedWidget != nil {
+		guigui.Focus(m.focusedWidget)
+	} else if w := context.FocusedWidget(); w != nil {
+		guigui.Blur(w)
+	}
+	m.focusSaved = false
+	m.focusedWidget = nil
+}
+
+func (m *MenuBar) setHighlightedMenuIndex(context *guigui.Context, index int) {
+	if index < 0 || index >= len(m.menus) {
+		index = -1
+	}
+	if m.highlightedMenuIndexPlus1-1 == index {
+		return
+	}
+	if index >= 0 && m.highlightedMenuIndexPlus1 == 0 {
+		m.saveFocus(context)
+		guigui.Focus(m)
+	}
+	m.highlightedMenuIndexPlus1 = index + 1
+	if index < 0 {
+		m.restoreFocus(context)
+	}
+	guigui.RequestRedraw(m)
+}
+
+func (m *MenuBar) titleIndexAt(position image.Point) int {
+	for i, t := range m.titles {
+		if position.In(guigui.VisibleBounds(t)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// areMnemonicsVisible reports whether the mnemonics are underlined. The mnemonics are shown only while menus are operated by keys.
+func (m *MenuBar) areMnemonicsVisible(context *guigui.Context) bool {
+	return context.Input().IsKeyPressed(ebiten.KeyAlt) || m.highlightedMenuIndexPlus1 > 0 || m.openMenuIndexPlus1 > 0 && m.openedByKey
+}
+
+func (m *MenuBar) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	input := context.Input()
+
+	// Open a menu by Alt and the mnemonic. While a title is highlighted, the mnemonic alone opens the menu.
+	if input.IsKeyPressed(ebiten.KeyAlt) || m.highlightedMenuIndexPlus1 > 0 {
+		for i, menu := range m.menus {
+			if key, ok := mnemonicKey(menu.Title); ok && input.IsKeyJustPressed(key) {
+				m.openMenu(context, i, true)
+				return guigui.HandleInputByWidget(m)
+			}
+		}
+	}
+
+	if idx := m.highlightedMenuIndexPlus1 - 1; idx >= 0 {
+		n := len(m.menus)
+		switch {
+		case isKeyRepeating(input, ebiten.KeyLeft):
+			m.setHighlightedMenuIndex(context, (idx-1+n)%n)
+		case isKeyRepeating(input, ebiten.KeyRight):
+			m.setHighlightedMenuIndex(context, (idx+1)%n)
+		case input.IsKeyJustPressed(ebiten.KeyDown) || input.IsKeyJustPressed(ebiten.KeyEnter) || input.IsKeyJustPressed(ebiten.KeySpace):
+			m.openMenu(context, idx, true)
+		case input.IsKeyJustPressed(ebiten.KeyEscape):
+			m.setHighlightedMenuIndex(context, -1)
+		default:
+			return guigui.HandleInputResult{}
+		}
+		return guigui.HandleInputByWidget(m)
+	}
+
+	if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if idx := m.titleIndexAt(input.CursorPosition()); idx >= 0 {
+			m.openMenu(context, idx, false)
+			return guigui.HandleInputByWidget(m)
+		}
+	}
+	return guigui.HandleInputResult{}
+}
+
+func (m *MenuBar) Update(context *guigui.Context) error {
+	input := context.Input()
+
+	// The menu might be closed by clicking outside.
+	if m.openMenuIndexPlus1 > 0 && !m.popupMenu.isOpen() {
+		m.openMenuIndexPlus1 = 0
+		m.restoreFocus(context)
+		guigui.RequestRedraw(m)
+	}
+
+	// The title highlighted by keys is cancelled when the focus is moved to another widget.
+	if m.highlightedMenuIndexPlus1 > 0 && !guigui.IsFocused(m) {
+		m.highlightedMenuIndexPlus1 = 0
+		m.focusSaved = false
+		m.focusedWidget = nil
+		guigui.RequestRedraw(m)
+	}
+	if m.highlightedMenuIndexPlus1 > 0 && (input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || input.IsMouseButtonJustPressed(ebiten.MouseButtonRight)) {
+		m.setHighlightedMenuIndex(context, -1)
+	}
+
+	// Highlight the first title by pressing and releasing Alt alone.
+	if m.updateAltAlone(input) && m.openMenuIndexPlus1 == 0 {
+		if m.highlightedMenuIndexPlus1 > 0 {
+			m.setHighlightedMenuIndex(context, -1)
+		} else {
+			m.setHighlightedMenuIndex(context, 0)
+		}
+	}
+
+	cp := input.CursorPosition()
+	hovered := m.titleIndexAt(cp)
+	if m.hoveredMenuIndexPlus1 != hovered+1 {
+		m.hoveredMenuIndexPlus1 = hovered + 1
+		guigui.RequestRedraw(m)
+	}
+	// Switch the open menu by hovering over another title.
+	if idx := m.openMenuIndexPlus1 - 1; idx >= 0 && hovered >= 0 && hovered != idx && cp != m.lastCursorPosition {
+		m.openMenu(context, hovered, false)
+	}
+	m.lastCursorPosition = cp
+	return nil
+}
+
+// updateAltAlone reports whether Alt is released without pressing other keys or buttons.
+func (m *MenuBar) updateAltAlone(input *guigui.InputState) bool {
+	// Another key might be pressed at the same time as Alt.
+	if input.IsKeyJustPressed(ebiten.KeyAlt) {
+		m.altAlone = true
+	}
+	if !m.altAlone {
+		return false
+	}
+	if input.IsKeyJustReleased(ebiten.KeyAlt) {
+		m.altAlone = false
+		return true
+	}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if k == ebiten.KeyAlt || k == ebiten.KeyAltLeft || k == ebiten.KeyAltRight {
+			continue
+		}
+		if input.IsKeyPressed(k) {
+			m.altAlone = false
+			return false
+		}
+	}
+	for b := ebiten.MouseButton(0); b <= ebiten.MouseButtonMax; b++ {
+		if input.IsMouseButtonPressed(b) {
+			m.altAlone = false
+			return false
+		}
+	}
+	return false
+}
+
+func (m *MenuBar) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(m)
+	vector.DrawFilledRect(dst, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), Color(context.ColorMode(), ColorTypeBase, 0.95), false)
+	y := float32(bounds.Max.Y) - float32(context.Scale())/2
+	vector.StrokeLine(dst, float32(bounds.Min.X), y, float32(bounds.Max.X), y, float32(1*context.Scale()), Color(context.ColorMode(), ColorTypeBase, 0.8), false)
+}
+
+func (m *MenuBar) SetWidth(width int) {
+	if m.widthSet && m.width == width {
+		return
+	}
+	m.width = width
+	m.widthSet = true
+	guigui.RequestRelayout(m)
+}
+
+func (m *MenuBar) ResetWidth() {
+	if !m.widthSet {
+		return
+	}
+	m.widthSet = false
+	guigui.RequestRelayout(m)
+}
+
+func (m *MenuBar) Size(context *guigui.Context) (int, int) {
+	h := int(LineHeight(context)) + UnitSize(context)/2
+	if m.widthSet {
+		return m.width, h
+	}
+	var w int
+	for _, t := range m.titles {
+		tw, _ := t.Size(context)
+		w += tw
+	}
+	return w, h
+}
+
+func (m *MenuBar) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role: guigui.AccessibilityRoleMenuBar,
+	}
+}
+
+func (m *MenuBar) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	return false
+}
+
+type menuBarTitle struct {
+	guigui.DefaultWidget
+
+	menuBar *MenuBar
+	index   int
+
+	text Text
+
+	title            string
+	mnemonicsVisible bool
+}
+
+func (m *menuBarTitle) menu() *MenuBarMenu {
+	return &m.menuBar.menus[m.index]
+}
+
+func (m *menuBarTitle) isOpen() bool {
+	return m.menuBar.openMenuIndexPlus1-1 == m.index
+}
+
+func (m *menuBarTitle) isHighlighted() bool {
+	return m.isOpen() || m.menuBar.highlightedMenuIndexPlus1-1 == m.index
+}
+
+// updateText updates the text with the mnemonic underlined if needed.
+func (m *menuBarTitle) updateText(context *guigui.Context) {
+	title := m.menu().Title
+	visible := m.menuBar.areMnemonicsVisible(context)
+	if m.title == title && m.mnemonicsVisible == visible {
+		return
+	}
+	m.title = title
+	m.mnemonicsVisible = visible
+
+	str, idx := parseMenuTitle(title)
+	if !visible || idx < 0 {
+		m.text.SetText(str)
+		return
+	}
+	_, n := utf8.DecodeRuneInString(str[idx:])
+	m.text.SetStyledText([]TextRun{
+		{Text: str[:idx]},
+		{Text: str[idx : idx+n], Style: TextStyle{Underline: true}},
+		{Text: str[idx+n:]},
+	})
+}
+
+func (m *menuBarTitle) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	_, h := m.Size(context)
+	m.text.SetHeight(h)
+	m.text.SetVerticalAlign(VerticalAlignMiddle)
+	if m.isHighlighted() {
+		m.text.SetColor(DefaultActiveListItemTextColor(context))
+	} else {
+		m.text.SetColor(nil)
+	}
+	guigui.SetPosition(&m.text, guigui.Position(m).Add(image.Pt(UnitSize(context)/2, 0)))
+	appender.AppendChildWidget(&m.text)
+}
+
+func (m *menuBarTitle) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(m)
+	// Leave a margin around the highlight.
+	margin := UnitSize(context) / 8
+	bounds = bounds.Inset(margin)
+	switch {
+	case m.isHighlighted():
+		DrawRoundedRect(context, dst, bounds, Color(context.ColorMode(), ColorTypeAccent, 0.5), RoundedCornerRadius(context))
+	case m.menuBar.hoveredMenuIndexPlus1-1 == m.index:
+		DrawRoundedRect(context, dst, bounds, Color(context.ColorMode(), ColorTypeBase, 0.9), RoundedCornerRadius(context))
+	}
+}
+
+func (m *menuBarTitle) Size(context *guigui.Context) (int, int) {
+	w, _ := m.text.TextSize(context)
+	return w + UnitSize(context), int(LineHeight(context)) + UnitSize(context)/2
+}
+
+func (m *menuBarTitle) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role:     guigui.AccessibilityRoleMenuItem,
+		Label:    m.text.Text(),
+		Expanded: m.isOpen(),
+		Actions:  []guigui.AccessibilityAction{guigui.AccessibilityActionPress},
+		// The items of the open menu are exposed by the popup menu.
+		Leaf: true,
+	}
+}
+
+func (m *menuBarTitle) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress {
+		return false
+	}
+	m.menuBar.openMenu(context, m.index, true)
+	return true
+}
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func TestParseMenuTitle(t *testing.T) {
+	testCases := []struct {
+		title     string
+		wantTitle string
+		wantIndex int
+	}{
+		{title: "", wantTitle: "", wantIndex: -1},
+		{title: "File", wantTitle: "File", wantIndex: -1},
+		{title: "&File", wantTitle: "File", wantIndex: 0},
+		{title: "E&xit", wantTitle: "Exit", wantIndex: 1},
+		{title: "Save && Exit", wantTitle: "Save & Exit", wantIndex: -1},
+		{title: "A&&B&C", wantTitle: "A&BC", wantIndex: 3},
+		{title: "&&&X", wantTitle: "&X", wantIndex: 1},
+		{title: "F&i&le", wantTitle: "File", wantIndex: 1},
+		{title: "End&", wantTitle: "End&", wantIndex: -1},
+		{title: "&Über", wantTitle: "Über", wantIndex: 0},
+	}
+	for _, tc := range testCases {
+		title, idx := parseMenuTitle(tc.title)
+		if title != tc.wantTitle || idx != tc.wantIndex {
+			t.Errorf("parseMenuTitle(%q): got: (%q, %d), want: (%q, %d)", tc.title, title, idx, tc.wantTitle, tc.wantIndex)
+		}
+	}
+}
+
+func TestMnemonicKey(t *testing.T) {
+	testCases := []struct {
+		title   string
+		wantKey ebiten.Key
+		wantOK  bool
+	}{
+		{title: "&File", wantKey: ebiten.KeyF, wantOK: true},
+		{title: "Vie&w", wantKey: ebiten.KeyW, wantOK: true},
+		{title: "&1 Recent", wantKey: ebiten.KeyDigit1, wantOK: true},
+		{title: "Save && Exit", wantOK: false},
+		{title: "&Über", wantOK: false},
+	}
+	for _, tc := range testCases {
+		key, ok := mnemonicKey(tc.title)
+		if ok != tc.wantOK || ok && key != tc.wantKey {
+			t.Errorf("mnemonicKey(%q): got: (%v, %t), want: (%v, %t)", tc.title, key, ok, tc.wantKey, tc.wantOK)
+		}
+	}
+}
+
+func newTestMenuBar() *MenuBar {
+	var menuBar MenuBar
+	menuBar.SetMenus([]MenuBarMenu{
+		{
+			Title: "&File",
+			Items: []PopupMenuItem{{ID: "open", Text: "Open"}},
+		},
+		{
+			Title: "&Edit",
+			Items: []PopupMenuItem{{ID: "copy", Text: "Copy"}},
+		},
+		{
+			Title: "&View",
+			Items: []PopupMenuItem{{ID: "zoom", Text: "Zoom"}},
+		},
+	})
+	return &menuBar
+}
+
+func TestMenuBarAltAlone(t *testing.T) {
+	menuBar := newTestMenuBar()
+	var textField TextField
+	d := newTestDriver(menuBar, &textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pressing and releasing Alt alone highlights the first title.
+	pressKeys(t, d, 1, ebiten.KeyAlt)
+	if got, want := menuBar.highlightedMenuIndexPlus1-1, 0; got != want {
+		t.Errorf("highlighted menu index: got: %d, want: %d", got, want)
+	}
+	if got, want := d.FocusedWidget(), guigui.Widget(menuBar); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	if !menuBar.areMnemonicsVisible(d.Context()) {
+		t.Errorf("areMnemonicsVisible(): got: false, want: true")
+	}
+
+	pressKeys(t, d, 2, ebiten.KeyRight)
+	if got, want := menuBar.highlightedMenuIndexPlus1-1, 1; got != want {
+		t.Errorf("highlighted menu index after Right: got: %d, want: %d", got, want)
+	}
+	pressKeys(t, d, 2, ebiten.KeyLeft)
+	pressKeys(t, d, 2, ebiten.KeyLeft)
+	if got, want := menuBar.highlightedMenuIndexPlus1-1, 2; got != want {
+		t.Errorf("highlighted menu index after Left twice: got: %d, want: %d", got, want)
+	}
+
+	// Pressing Alt alone again cancels the highlight, and the focus is restored.
+	pressKeys(t, d, 1, ebiten.KeyAlt)
+	if got, want := menuBar.highlightedMenuIndexPlus1, 0; got != want {
+		t.Errorf("highlighted menu index plus 1: got: %d, want: %d", got, want)
+	}
+	if got, want := d.FocusedWidget(), guigui.Widget(&textField.text); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+
+	// Alt with another key doesn't highlight the title.
+	pressKeys(t, d, 1, ebiten.KeyAlt, ebiten.KeyQ)
+	if got, want := menuBar.highlightedMenuIndexPlus1, 0; got != want {
+		t.Errorf("highlighted menu index plus 1 after Alt+Q: got: %d, want: %d", got, want)
+	}
+}
+
+func TestMenuBarAdjacentMenus(t *testing.T) {
+	menuBar := newTestMenuBar()
+	var textField TextField
+	d := newTestDriver(menuBar, &textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	openMenuID := func() string {
+		t.Helper()
+		if !guigui.IsVisible(&menuBar.popupMenu.popup) {
+			t.Fatalf("the menu is not visible")
+		}
+		return menuBar.popupMenu.items[0].ID
+	}
+
+	// Alt and the mnemonic open the menu.
+	pressKeys(t, d, 1, ebiten.KeyAlt, ebiten.KeyE)
+	if got, want := menuBar.openMenuIndexPlus1-1, 1; got != want {
+		t.Errorf("open menu index: got: %d, want: %d", got, want)
+	}
+	if got, want := openMenuID(), "copy"; got != want {
+		t.Errorf("item ID: got: %q, want: %q", got, want)
+	}
+
+	// Left and Right move to the adjacent menus, wrapping around.
+	for _, tc := range []struct {
+		key    ebiten.Key
+		index  int
+		itemID string
+	}{
+		{key: ebiten.KeyRight, index: 2, itemID: "zoom"},
+		{key: ebiten.KeyRight, index: 0, itemID: "open"},
+		{key: ebiten.KeyLeft, index: 2, itemID: "zoom"},
+	} {
+		pressKeys(t, d, 1, tc.key)
+		if got := menuBar.openMenuIndexPlus1 - 1; got != tc.index {
+			t.Errorf("open menu index after %v: got: %d, want: %d", tc.key, got, tc.index)
+		}
+		if got := openMenuID(); got != tc.itemID {
+			t.Errorf("item ID after %v: got: %q, want: %q", tc.key, got, tc.itemID)
+		}
+	}
+
+	// Closing the menu restores the focus.
+	pressKeys(t, d, 1, ebiten.KeyEscape)
+	if got, want := menuBar.openMenuIndexPlus1, 0; got != want {
+		t.Errorf("open menu index plus 1: got: %d, want: %d", got, want)
+	}
+	if got, want := d.FocusedWidget(), guigui.Widget(&textField.text); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+}
@@ -0,0 +1,520 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/text/language"
+
+	"github.com/xackery/guigui"
+)
+
+// NumberInput is a text field to input a number.
+//
+// The value is committed when Enter is pressed, when the number input loses the focus, or when the value is stepped.
+// A text that cannot be parsed is reverted to the current value at the commit.
+type NumberInput struct {
+	guigui.DefaultWidget
+
+	textField  TextField
+	upButton   numberInputButton
+	downButton numberInputButton
+
+	value              float64
+	minimum            float64
+	maximum            float64
+	minimumSet         bool
+	maximumSet         bool
+	step               float64
+	decimalPlacesPlus1 int
+
+	// formattedText is the text formatted at the last commit.
+	formattedText string
+	wheelY        float64
+
+	onValueChanged func(value float64)
+}
+
+// SetOnValueChanged sets the function called when the value is changed by a commit.
+func (n *NumberInput) SetOnValueChanged(f func(value float64)) {
+	n.onValueChanged = f
+}
+
+func (n *NumberInput) Value() float64 {
+	return n.value
+}
+
+// SetValue sets the value. The value is clamped into the range.
+//
+// SetValue doesn't call the function set by SetOnValueChanged.
+func (n *NumberInput) SetValue(value float64) {
+	value = n.clamp(value)
+	if n.value == value && n.formattedText != "" {
+		return
+	}
+	n.value = value
+	n.formattedText = ""
+	guigui.RequestRelayout(n)
+}
+
+// SetMinimum sets the minimum value.
+func (n *NumberInput) SetMinimum(minimum float64) {
+	if n.minimumSet && n.minimum == minimum {
+		return
+	}
+	n.minimum = minimum
+	n.minimumSet = true
+	n.SetValue(n.value)
+}
+
+// SetMaximum sets the maximum value.
+func (n *NumberInput) SetMaximum(maximum float64) {
+	if n.maximumSet && n.maximum == maximum {
+		return
+	}
+	n.maximum = maximum
+	n.maximumSet = true
+	n.SetValue(n.value)
+}
+
+// SetStep sets the amount to increase or decrease the value by the buttons, the arrow keys, and the wheel.
+// The default step is 1.
+func (n *NumberInput) SetStep(step float64) {
+	if n.step == step {
+		return
+	}
+	n.step = step
+	guigui.RequestRelayout(n)
+}
+
+// SetDecimalPlaces sets the number of digits after the decimal separator.
+// If decimalPlaces is negative, the smallest number of digits to represent the value is used.
+// The default is -1.
+func (n *NumberInput) SetDecimalPlaces(decimalPlaces int) {
+	if decimalPlaces < 0 {
+		decimalPlaces = -1
+	}
+	if n.decimalPlacesPlus1 == decimalPlaces+1 {
+		return
+	}
+	n.decimalPlacesPlus1 = decimalPlaces + 1
+	n.formattedText = ""
+	guigui.RequestRelayout(n)
+}
+
+func (n *NumberInput) SetEditable(editable bool) {
+	n.textField.SetEditable(editable)
+}
+
+func (n *NumberInput) SetSize(context *guigui.Context, width, height int) {
+	n.textField.SetSize(context, width, height)
+}
+
+func (n *NumberInput) Size(context *guigui.Context) (int, int) {
+	return n.textField.Size(context)
+}
+
+func (n *NumberInput) stepOrDefault() float64 {
+	if n.step <= 0 {
+		return 1
+	}
+	return n.step
+}
+
+func (n *NumberInput) clamp(value float64) float64 {
+	if n.minimumSet {
+		value = max(value, n.minimum)
+	}
+	if n.maximumSet {
+		value = min(value, n.maximum)
+	}
+	return value
+}
+
+func (n *NumberInput) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	// Do not overwrite the text while the text is being edited, unless the value is set by SetValue.
+	text := numberFormatForContext(context).format(n.value, n.decimalPlacesPlus1-1)
+	if n.formattedText == "" || !guigui.HasFocusedChildWidget(n) && n.textField.Text() != text {
+		n.formattedText = text
+		n.textField.SetText(text)
+	}
+
+	n.textField.SetHorizontalAlign(HorizontalAlignEnd)
+	n.textField.SetMultiline(false)
+	n.textField.SetOnEnterPressed(func(text string) {
+		n.commit(context)
+	})
+	n.textField.text.keyHandler = func(context *guigui.Context) bool {
+		return n.handleKeys(context)
+	}
+	n.textField.paddingEnd = numberInputButtonWidth(context)
+
+	guigui.SetPosition(&n.textField, guigui.Position(n))
+	appender.AppendChildWidget(&n.textField)
+
+	n.upButton.up = true
+	n.upButton.onDown = func() {
+		n.stepBy(context, 1)
+	}
+	n.downButton.onDown = func() {
+		n.stepBy(context, -1)
+	}
+	if n.textField.readonly {
+		guigui.Disable(&n.upButton)
+		guigui.Disable(&n.downButton)
+	} else {
+		guigui.Enable(&n.upButton)
+		guigui.Enable(&n.downButton)
+	}
+
+	b := guigui.Bounds(n)
+	w := numberInputButtonWidth(context)
+	h := b.Dy() / 2
+	n.upButton.width = w
+	n.upButton.height = h
+	n.downButton.width = w
+	n.downButton.height = b.Dy() - h
+	guigui.SetPosition(&n.upButton, image.Pt(b.Max.X-w, b.Min.Y))
+	appender.AppendChildWidget(&n.upButton)
+	guigui.SetPosition(&n.downButton, image.Pt(b.Max.X-w, b.Min.Y+h))
+	appender.AppendChildWidget(&n.downButton)
+}
+
+func (n *NumberInput) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if n.textField.readonly || !guigui.HasFocusedChildWidget(n) {
+		n.wheelY = 0
+		return guigui.HandleInputResult{}
+	}
+	if !context.Input().CursorPosition().In(guigui.VisibleBounds(n)) {
+		n.wheelY = 0
+		return guigui.HandleInputResult{}
+	}
+	_, dy := context.Input().Wheel()
+	if dy == 0 {
+		return guigui.HandleInputResult{}
+	}
+	// Accumulate the wheel movement so that a smooth scroll by a trackpad doesn't step too fast.
+	n.wheelY += dy
+	if math.Abs(n.wheelY) < 1 {
+		return guigui.HandleInputByWidget(n)
+	}
+	steps := math.Trunc(n.wheelY)
+	n.wheelY -= steps
+	n.stepBy(context, steps)
+	return guigui.HandleInputByWidget(n)
+}
+
+func (n *NumberInput) handleKeys(context *guigui.Context) bool {
+	if n.textField.readonly {
+		return false
+	}
+	input := context.Input()
+	switch {
+	case isKeyRepeating(input, ebiten.KeyUp):
+		n.stepBy(context, 1)
+		return true
+	case isKeyRepeating(input, ebiten.KeyDown):
+		n.stepBy(context, -1)
+		return true
+	}
+	return false
+}
+
+func (n *NumberInput) OnFocusChanged(context *guigui.Context, event guigui.FocusChangedEvent) {
+	if !event.FocusWithin {
+		n.commit(context)
+	}
+}
+
+// commit parses the text and updates the value.
+func (n *NumberInput) commit(context *guigui.Context) {
+	format := numberFormatForContext(context)
+	value, ok := format.parse(n.textField.Text())
+	if !ok {
+		value = n.value
+	}
+	n.setValueAndNotify(context, value)
+}
+
+func (n *NumberInput) stepBy(context *guigui.Context, steps float64) {
+	format := numberFormatForContext(context)
+	value, ok := format.parse(n.textField.Text())
+	if !ok {
+		value = n.value
+	}
+	step := n.stepOrDefault()
+	// Round the value so that the errors of the floating-point numbers are not accumulated, e.g. 0.1+0.2 = 0.30000000000000004.
+	value = roundToDecimalPlaces(value+steps*step, max(decimalPlacesOf(value), decimalPlacesOf(step)))
+	n.setValueAndNotify(context, value)
+	n.textField.SelectAll()
+}
+
+// decimalPlacesOf returns the number of digits after the decimal point in the shortest representation of the value.
+func decimalPlacesOf(value float64) int {
+	str := strconv.FormatFloat(value, 'f', -1, 64)
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		return len(str) - i - 1
+	}
+	return 0
+}
+
+func roundToDecimalPlaces(value float64, decimalPlaces int) float64 {
+	v, err := strconv.ParseFloat(strconv.FormatFloat(value, 'f', decimalPlaces, 64), 64)
+	if err != nil {
+		return value
+	}
+	return v
+}
+
+func (n *NumberInput) setValueAndNotify(context *guigui.Context, value float64) {
+	old := n.value
+	n.value = n.clamp(value)
+
+	// Reformat the text even if the value is not changed, as the text might be edited.
+	n.formattedText = numberFormatForContext(context).format(n.value, n.decimalPlacesPlus1-1)
+	n.textField.SetText(n.formattedText)
+	guigui.RequestRedraw(n)
+
+	if n.value != old && n.onValueChanged != nil {
+		n.onValueChanged(n.value)
+	}
+}
+
+// numberFormat is a locale-dependent notation of numbers.
+type numberFormat struct {
+	decimalSeparator string
+	groupSeparator   string
+}
+
+var (
+	numberFormatPeriod      = numberFormat{decimalSeparator: ".", groupSeparator: ","}
+	numberFormatCommaPeriod = numberFormat{decimalSeparator: ",", groupSeparator: "."}
+	numberFormatCommaSpace  = numberFormat{decimalSeparator: ",", groupSeparator: " "}
+	numberFormatSwiss       = numberFormat{decimalSeparator: ".", groupSeparator: "’"}
+)
+
+// numberFormats is the notations of numbers by languages. The other languages use numberFormatPeriod.
+var numberFormats = map[string]numberFormat{
+	"da": numberFormatCommaPeriod,
+	"de": numberFormatCommaPeriod,
+	"el": numberFormatCommaPeriod,
+	"es": numberFormatCommaPeriod,
+	"id": numberFormatCommaPeriod,
+	"it": numberFormatCommaPeriod,
+	"nl": numberFormatCommaPeriod,
+	"pt": numberFormatCommaPeriod,
+	"ro": numberFormatCommaPeriod,
+	"tr": numberFormatCommaPeriod,
+	"vi": numberFormatCommaPeriod,
+	"bg": numberFormatCommaSpace,
+	"cs": numberFormatCommaSpace,
+	"fi": numberFormatCommaSpace,
+	"fr": numberFormatCommaSpace,
+	"hu": numberFormatCommaSpace,
+	"nb": numberFormatCommaSpace,
+	"no": numberFormatCommaSpace,
+	"pl": numberFormatCommaSpace,
+	"ru": numberFormatCommaSpace,
+	"sk": numberFormatCommaSpace,
+	"sv": numberFormatCommaSpace,
+	"uk": numberFormatCommaSpace,
+}
+
+func numberFormatForContext(context *guigui.Context) numberFormat {
+	for _, locale := range context.AppendLocales(nil) {
+		base, conf := locale.Base()
+		if conf == language.No {
+			continue
+		}
+		if region, _ := locale.Region(); region.String() == "CH" && (base.String() == "de" || base.String() == "it") {
+			return numberFormatSwiss
+		}
+		if f, ok := numberFormats[base.String()]; ok {
+			return f
+		}
+		return numberFormatPeriod
+	}
+	return numberFormatPeriod
+}
+
+// format formats the value. If decimalPlaces is negative, the smallest number of digits is used.
+func (n numberFormat) format(value float64, decimalPlaces int) string {
+	if decimalPlaces < 0 {
+		// Drop the errors of the floating-point numbers by rounding the value to 15 significant digits,
+		// so that e.g. 0.30000000000000004 is formatted as 0.3.
+		if v, err := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 15, 64), 64); err == nil {
+			value = v
+		}
+	}
+	str := strconv.FormatFloat(value, 'f', decimalPlaces, 64)
+	if str == "-0" || strings.HasPrefix(str, "-0.") && strings.Trim(str[len("-0."):], "0") == "" {
+		str = str[1:]
+	}
+	return strings.Replace(str, ".", n.decimalSeparator, 1)
+}
+
+// parse parses the text.
+//
+// Group separators are allowed only between groups of three digits in the integer part, like "1,234,567".
+// A text with misplaced group separators, like "1.5" in a language using "." as a group separator, is rejected.
+// Spaces are also treated as group separators.
+// The other characters than digits, a leading sign, and the separators are not allowed.
+func (n numberFormat) parse(text string) (float64, bool) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\u2212", "-"))
+
+	intPart, fracPart, hasFracPart := strings.Cut(text, n.decimalSeparator)
+	if strings.ContainsFunc(fracPart, n.isGroupSeparator) {
+		return 0, false
+	}
+
+	var sign string
+	if strings.HasPrefix(intPart, "-") || strings.HasPrefix(intPart, "+") {
+		sign = intPart[:1]
+		intPart = intPart[1:]
+	}
+	if strings.ContainsFunc(intPart, n.isGroupSeparator) {
+		var groups []string
+		var start int
+		for i, r := range intPart {
+			if n.isGroupSeparator(r) {
+				groups = append(groups, intPart[start:i])
+				start = i + utf8.RuneLen(r)
+			}
+		}
+		groups = append(groups, intPart[start:])
+		for i, group := range groups {
+			if i == 0 && (len(group) == 0 || len(group) > 3) || i > 0 && len(group) != 3 {
+				return 0, false
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, false
+	}
+
+	text = sign + intPart
+	if hasFracPart {
+		text += "." + fracPart
+	}
+	if text == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+func isDigits(str string) bool {
+	return strings.Trim(str, "0123456789") == ""
+}
+
+func (n numberFormat) isGroupSeparator(r rune) bool {
+	return unicode.IsSpace(r) || string(r) == n.groupSeparator
+}
+
+func numberInputButtonWidth(context *guigui.Context) int {
+	return UnitSize(context) * 3 / 4
+}
+
+type numberInputButton struct {
+	guigui.DefaultWidget
+
+	mouseOverlay guigui.MouseOverlay
+	focusRing    focusRing
+
+	up     bool
+	width  int
+	height int
+
+	onDown func()
+}
+
+func (n *numberInputButton) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	n.mouseOverlay.SetOnDown(func(mouseButton ebiten.MouseButton, cursorPosition image.Point) {
+		if mouseButton != ebiten.MouseButtonLeft {
+			return
+		}
+		if n.onDown != nil {
+			n.onDown()
+		}
+	})
+	guigui.SetPosition(&n.mouseOverlay, guigui.Position(n))
+	appender.AppendChildWidget(&n.mouseOverlay)
+
+	guigui.SetFocusable(n, true)
+	appendFocusRing(context, appender, &n.focusRing, n)
+}
+
+func (n *numberInputButton) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if guigui.IsEnabled(n) && guigui.HasFocusedChildWidget(n) && isActivationKeyJustPressed(context.Input()) {
+		if n.onDown != nil {
+			n.onDown()
+		}
+		return guigui.HandleInputByWidget(n)
+	}
+
+	// Repeat stepping while the button is pressed. The first step is made at the press.
+	if d := context.Input().MouseButtonPressDuration(ebiten.MouseButtonLeft); n.mouseOverlay.IsPressing() && d >= 24 && (d-24)%4 == 0 {
+		if n.onDown != nil {
+			n.onDown()
+		}
+		return guigui.HandleInputByWidget(n)
+	}
+	return guigui.HandleInputResult{}
+}
+
+func (n *numberInputButton) CursorShape(context *guigui.Context) (ebiten.CursorShapeType, bool) {
+	if guigui.IsEnabled(n) && n.mouseOverlay.IsHovering() {
+		return ebiten.CursorShapePointer, true
+	}
+	return 0, false
+}
+
+func (n *numberInputButton) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(n)
+	cm := context.ColorMode()
+	if guigui.IsEnabled(n) && n.mouseOverlay.IsHovering() {
+		clr := Color(cm, ColorTypeBase, 0.8)
+		if n.mouseOverlay.IsPressing() {
+			clr = Color(cm, ColorTypeBase, 0.75)
+		}
+		vector.DrawFilledRect(dst, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), clr, false)
+	}
+
+	clr := Color(cm, ColorTypeBase, 0.3)
+	if !guigui.IsEnabled(n) {
+		clr = Color(cm, ColorTypeBase, 0.6)
+	}
+	cx := float32(bounds.Min.X) + float32(bounds.Dx())/2
+	cy := float32(bounds.Min.Y) + float32(bounds.Dy())/2
+	w := float32(bounds.Dx()) / 3
+	h := w / 2
+	var path vector.Path
+	if n.up {
+		path.MoveTo(cx-w/2, cy+h/2)
+		path.LineTo(cx, cy-h/2)
+		path.LineTo(cx+w/2, cy+h/2)
+	} else {
+		path.MoveTo(cx-w/2, cy-h/2)
+		path.LineTo(cx, cy+h/2)
+		path.LineTo(cx+w/2, cy-h/2)
+	}
+	path.Close()
+	vector.DrawFilledPath(dst, &path, clr, true, vector.FillRuleNonZero)
+}
+
+func (n *numberInputButton) Size(context *guigui.Context) (int, int) {
+	return n.width, n.height
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func TestNumberInputSetValueWithoutInput(t *testing.T) {
+	var numberInput NumberInput
+	numberInput.SetValue(1)
+	d := newTestDriver(&numberInput)
+	if err := d.StepN(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := numberInput.textField.Text(), "1"; got != want {
+		t.Errorf("Text(): got: %q, want: %q", got, want)
+	}
+
+	// Setting the value without any input must update the text.
+	for _, tc := range []struct {
+		set  func()
+		want string
+	}{
+		{set: func() { numberInput.SetValue(5) }, want: "5"},
+		{set: func() { numberInput.SetMaximum(3) }, want: "3"},
+		{set: func() { numberInput.SetMaximum(10) }, want: "3"},
+		{set: func() { numberInput.SetMinimum(6) }, want: "6"},
+		{set: func() { numberInput.SetDecimalPlaces(2) }, want: "6.00"},
+	} {
+		tc.set()
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if got := numberInput.textField.Text(); got != tc.want {
+			t.Errorf("Text(): got: %q, want: %q", got, tc.want)
+		}
+	}
+}
+
+func TestNumberFormatFormat(t *testing.T) {
+	testCases := []struct {
+		format        numberFormat
+		value         float64
+		decimalPlaces int
+		want          string
+	}{
+		{format: numberFormatPeriod, value: 0, decimalPlaces: -1, want: "0"},
+		{format: numberFormatPeriod, value: 1.5, decimalPlaces: -1, want: "1.5"},
+		{format: numberFormatPeriod, value: 0.1 + 0.2, decimalPlaces: -1, want: "0.3"},
+		{format: numberFormatPeriod, value: 1234567.25, decimalPlaces: -1, want: "1234567.25"},
+		{format: numberFormatPeriod, value: 1.5, decimalPlaces: 2, want: "1.50"},
+		{format: numberFormatPeriod, value: 1.005, decimalPlaces: 0, want: "1"},
+		{format: numberFormatPeriod, value: -0.001, decimalPlaces: 2, want: "0.00"},
+		{format: numberFormatPeriod, value: -1.5, decimalPlaces: -1, want: "-1.5"},
+		{format: numberFormatCommaPeriod, value: 1.5, decimalPlaces: -1, want: "1,5"},
+		{format: numberFormatCommaPeriod, value: 0.1 + 0.2, decimalPlaces: 2, want: "0,30"},
+		{format: numberFormatCommaSpace, value: 1234.5, decimalPlaces: -1, want: "1234,5"},
+		{format: numberFormatSwiss, value: 1234.5, decimalPlaces: -1, want: "1234.5"},
+	}
+	for _, tc := range testCases {
+		if got := tc.format.format(tc.value, tc.decimalPlaces); got != tc.want {
+			t.Errorf("%+v.format(%v, %d): got: %q, want: %q", tc.format, tc.value, tc.decimalPlaces, got, tc.want)
+		}
+	}
+}
+
+func TestNumberFormatParse(t *testing.T) {
+	testCases := []struct {
+		format numberFormat
+		text   string
+		want   float64
+		ok     bool
+	}{
+		{format: numberFormatPeriod, text: "1.5", want: 1.5, ok: true},
+		{format: numberFormatPeriod, text: " -1.5 ", want: -1.5, ok: true},
+		{format: numberFormatPeriod, text: "\u22121.5", want: -1.5, ok: true},
+		{format: numberFormatPeriod, text: "+2", want: 2, ok: true},
+		{format: numberFormatPeriod, text: "1,234,567.5", want: 1234567.5, ok: true},
+		{format: numberFormatPeriod, text: "1 234", want: 1234, ok: true},
+		{format: numberFormatPeriod, text: "1,5", ok: false},
+		{format: numberFormatPeriod, text: "1234,567", ok: false},
+		{format: numberFormatPeriod, text: ",123", ok: false},
+		{format: numberFormatPeriod, text: "1.234,5", ok: false},
+		{format: numberFormatPeriod, text: "1.5.5", ok: false},
+		{format: numberFormatPeriod, text: "", ok: false},
+		{format: numberFormatPeriod, text: "-", ok: false},
+		{format: numberFormatPeriod, text: "abc", ok: false},
+		{format: numberFormatPeriod, text: "NaN", ok: false},
+		{format: numberFormatPeriod, text: "Inf", ok: false},
+		{format: numberFormatCommaPeriod, text: "1,5", want: 1.5, ok: true},
+		{format: numberFormatCommaPeriod, text: "1.234,5", want: 1234.5, ok: true},
+		{format: numberFormatCommaPeriod, text: "1.5", ok: false},
+		{format: numberFormatCommaPeriod, text: "1,5.5", ok: false},
+		{format: numberFormatCommaSpace, text: "1 234,5", want: 1234.5, ok: true},
+		{format: numberFormatCommaSpace, text: "1\u00a0234\u202f567", want: 1234567, ok: true},
+		{format: numberFormatCommaSpace, text: "12 34", ok: false},
+		{format: numberFormatCommaSpace, text: "1.5", ok: false},
+		{format: numberFormatPeriod, text: ".5", want: 0.5, ok: true},
+		{format: numberFormatPeriod, text: "1e3", ok: false},
+		{format: numberFormatSwiss, text: "1’234.5", want: 1234.5, ok: true},
+		{format: numberFormatSwiss, text: "1’2", ok: false},
+	}
+	for _, tc := range testCases {
+		got, ok := tc.format.parse(tc.text)
+		if ok != tc.ok || ok && got != tc.want {
+			t.Errorf("%+v.parse(%q): got: (%v, %t), want: (%v, %t)", tc.format, tc.text, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestNumberInputStepByFraction(t *testing.T) {
+	var numberInput NumberInput
+	numberInput.SetEditable(true)
+	numberInput.SetStep(0.1)
+	d := newTestDriver(&numberInput)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&numberInput.textField.text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		pressKeys(t, d, 2, ebiten.KeyUp)
+	}
+	if got, want := numberInput.Value(), 0.3; got != want {
+		t.Errorf("Value(): got: %v, want: %v", got, want)
+	}
+	if got, want := numberInput.textField.Text(), "0.3"; got != want {
+		t.Errorf("Text(): got: %q, want: %q", got, want)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"github.com/xackery/guigui"
 )
@@ -35,6 +34,15 @@ type Popup struct {
 	hiding                 bool
 	backgroundBlurred      bool
 	closeByClickingOutside bool
+	open                   bool
+
+	// modeless reports whether the popup lets other widgets handle inputs outside of the content.
+	// A submenu of PopupMenu is modeless so that the parent menu can handle the cursor.
+	modeless bool
+
+	// handleInput is called while the popup is open before the popup handles inputs.
+	// As the popup is above its parent, the parent can handle inputs, e.g. keys, with this before the popup aborts them.
+	handleInput func(context *guigui.Context) guigui.HandleInputResult
 
 	initOnce sync.Once
 }
@@ -53,7 +61,13 @@ func (p *Popup) SetContentBounds(bounds image.Rectangle) {
 }
 
 func (p *Popup) SetBackgroundBlurred(blurBackground bool) {
+	if p.backgroundBlurred == blurBackground {
+		return
+	}
 	p.backgroundBlurred = blurBackground
+	guigui.RequestRedraw(p)
+	// The blurred background is a child widget.
+	guigui.RequestRelayout(p)
 }
 
 func (p *Popup) SetCloseByClickingOutside(closeByClickingOutside bool) {
@@ -62,9 +76,15 @@ func (p *Popup) SetCloseByClickingOutside(closeByClickingOutside bool) {
 
 func (p *Popup) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
 	p.initOnce.Do(func() {
-		guigui.Hide(p)
+		// A popup might be opened before its first layout, e.g. a popup created lazily.
+		if !p.open {
+			guigui.Hide(p)
+		}
 	})
 
+	// A modal popup keeps the keyboard navigation inside it.
+	guigui.SetFocusTrap(p, !p.modeless)
+
 	if p.backgroundBlurred {
 		p.background.popup = p
 		appender.AppendChildWidget(&p.background)
@@ -75,17 +95,31 @@ func (p *Popup) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppe
 }
 
 func (p *Popup) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if p.open && p.handleInput != nil {
+		if r := p.handleInput(context); r.ShouldRaise() {
+			return r
+		}
+	}
+
+	if p.modeless {
+		if p.open && context.Input().CursorPosition().In(guigui.VisibleBounds(&p.content)) {
+			return guigui.AbortHandlingInput()
+		}
+		return guigui.HandleInputResult{}
+	}
+
 	if p.showing || p.hiding {
 		return guigui.AbortHandlingInput()
 	}
 
 	// As this editor is a modal dialog, do not let other widgets to handle inputs.
-	if image.Pt(ebiten.CursorPosition()).In(guigui.VisibleBounds(p)) {
+	input := context.Input()
+	if input.CursorPosition().In(guigui.VisibleBounds(p)) {
 		if p.closeByClickingOutside {
-			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+			if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
 				p.Close()
 				// Continue handling inputs so that clicking a right button can be handled by other widgets.
-				if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+				if input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
 					return guigui.HandleInputResult{}
 				}
 			}
@@ -98,11 +132,20 @@ func (p *Popup) Open() {
 	guigui.Show(p)
 	p.showing = true
 	p.hiding = false
+	p.open = true
+	guigui.RequestRelayout(p)
 }
 
 func (p *Popup) Close() {
 	p.showing = false
 	p.hiding = true
+	p.open = false
+	guigui.RequestRelayout(p)
+}
+
+// isOpen reports whether the popup is opened and not being closed.
+func (p *Popup) isOpen() bool {
+	return p.open
 }
 
 func (p *Popup) Update(context *guigui.Context) error {
@@ -163,7 +206,7 @@ func (p *popupContent) Layout(context *guigui.Context, appender *guigui.ChildWid
 }
 
 func (p *popupContent) HandleInput(context *guigui.Context) guigui.HandleInputResult {
-	if image.Pt(ebiten.CursorPosition()).In(guigui.VisibleBounds(p)) {
+	if context.Input().CursorPosition().In(guigui.VisibleBounds(p)) {
 		return guigui.AbortHandlingInput()
 	}
 	return guigui.HandleInputResult{}
@@ -175,8 +218,12 @@ func (p *popupContent) Draw(context *guigui.Context, dst *ebiten.Image) {
 }
 
 func (p *popupContent) setSize(width, height int) {
+	if p.width == width && p.height == height {
+		return
+	}
 	p.width = width
 	p.height = height
+	guigui.RequestRelayout(p)
 }
 
 func (p *popupContent) Size(context *guigui.Context) (int, int) {
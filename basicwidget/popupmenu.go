@@ -5,51 +5,251 @@ package basicwidget
 
 import (
 	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/xackery/guigui"
 )
 
+// PopupMenuItem is an item of a popup menu.
+type PopupMenuItem struct {
+	// ID identifies the item in the function set by SetOnItemSelected.
+	ID   string
+	Text string
+
+	// Icon is the image shown before the text. Icon can be nil.
+	Icon *ebiten.Image
+
+	// Shortcut is the text of the keyboard shortcut shown at the end of the item, like "Ctrl+S".
+	Shortcut string
+
+	// Separator reports whether the item is a separator line. The other fields are ignored for a separator.
+	Separator bool
+
+	Disabled bool
+
+	// Checkable reports whether choosing the item toggles Checked.
+	Checkable bool
+
+	// RadioGroup is the name of the group of radio items.
+	// Choosing a radio item checks the item and unchecks the other items of the same group in the same menu.
+	RadioGroup string
+
+	Checked bool
+
+	// Submenu is the items of the submenu opened from the item.
+	Submenu []PopupMenuItem
+
+	// Command is the name of the command executed by choosing the item. See also Context.RegisterCommand.
+	//
+	// If Text is empty, the title of the command is shown. If Shortcut is empty, the key chord bound to the command is shown.
+	// The item is disabled while the command is disabled.
+	Command string
+}
+
+func (p *PopupMenuItem) selectable() bool {
+	return !p.Separator && !p.Disabled
+}
+
+func (p *PopupMenuItem) hasMark() bool {
+	return p.Checkable || p.RadioGroup != "" || p.Checked
+}
+
+func clonePopupMenuItems(items []PopupMenuItem) []PopupMenuItem {
+	if items == nil {
+		return nil
+	}
+	cloned := make([]PopupMenuItem, len(items))
+	for i, item := range items {
+		cloned[i] = item
+		cloned[i].Submenu = clonePopupMenuItems(item.Submenu)
+	}
+	return cloned
+}
+
+func equalPopupMenuItems(items0, items1 []PopupMenuItem) bool {
+	return slices.EqualFunc(items0, items1, func(item0, item1 PopupMenuItem) bool {
+		return item0.ID == item1.ID &&
+			item0.Text == item1.Text &&
+			item0.Icon == item1.Icon &&
+			item0.Shortcut == item1.Shortcut &&
+			item0.Separator == item1.Separator &&
+			item0.Disabled == item1.Disabled &&
+			item0.Checkable == item1.Checkable &&
+			item0.RadioGroup == item1.RadioGroup &&
+			item0.Checked == item1.Checked &&
+			item0.Command == item1.Command &&
+			equalPopupMenuItems(item0.Submenu, item1.Submenu)
+	})
+}
+
+func submenuDelay() int {
+	return ebiten.TPS() / 4
+}
+
+// popupMenuColumns is the widths of the columns of the items in a menu.
+type popupMenuColumns struct {
+	mark     int
+	icon     int
+	text     int
+	shortcut int
+	arrow    int
+}
+
+func (p *popupMenuColumns) width() int {
+	return p.mark + p.icon + p.text + p.shortcut + p.arrow
+}
+
+// PopupMenu is a menu shown in a popup.
+//
+// The menu can be operated by the cursor and keys.
+// Up and Down move the highlighted item, Right and Enter open a submenu, Left and Escape close a submenu or the menu.
 type PopupMenu struct {
 	guigui.DefaultWidget
 
-	textList TextList
-	popup    Popup
+	popup       Popup
+	list        List
+	itemWidgets []*popupMenuItemWidget
+
+	items   []PopupMenuItem
+	columns popupMenuColumns
 
-	onClosed func(index int)
+	parent                       *PopupMenu
+	submenu                      *PopupMenu
+	submenuItemIndexPlus1        int
+	anchorBounds                 image.Rectangle
+	selectedItemIndexPlus1       int
+	keyHighlightedItemIndexPlus1 int
+	lastHoveredItemIndexPlus1    int
+	hoveringCount                int
+
+	onClosed       func(index int)
+	onItemSelected func(id string)
+
+	// onAdjacentMenu is called when Left or Right is pressed but there is no submenu to close or open.
+	// direction is -1 for Left and 1 for Right. onAdjacentMenu is used by MenuBar to move to the adjacent menu.
+	onAdjacentMenu func(direction int)
+
+	// onClose is called when the menu is closed by Close.
+	onClose func()
 }
 
+// SetOnClosed sets the function called when an item of the menu is chosen.
+// index is the index of the chosen item. The function is not called when an item of a submenu is chosen.
 func (p *PopupMenu) SetOnClosed(f func(index int)) {
 	p.onClosed = f
 }
 
+// SetOnItemSelected sets the function called when an item of the menu or its submenus is chosen.
+// id is the ID of the chosen item.
+func (p *PopupMenu) SetOnItemSelected(f func(id string)) {
+	p.onItemSelected = f
+}
+
 func (p *PopupMenu) IsPopup() bool {
 	// ??
 	return true
 }
 
 func (p *PopupMenu) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	p.updateItems(context)
+
 	p.popup.SetContent(func(context *guigui.Context, childAppender *ContainerChildWidgetAppender) {
-		p.textList.SetStyle(ListStyleMenu)
-		p.textList.list.SetOnItemSelected(func(index int) {
-			p.popup.Close()
-			if p.onClosed != nil {
-				p.onClosed(index)
+		p.list.SetStyle(ListStyleMenu)
+		p.list.SetOnItemSelected(func(index int) {
+			if index < 0 {
+				return
 			}
+			p.activate(context, index, false)
 		})
-		guigui.SetPosition(&p.textList, p.contentBounds(context).Min)
-		childAppender.AppendChildWidget(&p.textList)
+		guigui.SetPosition(&p.list, p.contentBounds(context).Min)
+		childAppender.AppendChildWidget(&p.list)
 	})
-	p.popup.SetCloseByClickingOutside(true)
+	p.popup.SetCloseByClickingOutside(p.parent == nil)
+	p.popup.modeless = p.parent != nil
+	// The popup of a submenu can be above the root menu's popup, so every popup lets the root menu handle the keys.
+	p.popup.handleInput = p.root().HandleInput
 	p.updateContentBounds(context)
 	appender.AppendChildWidget(&p.popup)
+
+	if p.submenu != nil {
+		// Put the submenu next to the item so that the first item of the submenu is aligned with the item.
+		if idx := p.submenuItemIndexPlus1 - 1; idx >= 0 && idx < len(p.items) {
+			r := p.list.itemRect(context, idx)
+			lp := guigui.Position(&p.list)
+			lw, _ := p.list.Size(context)
+			y := r.Min.Y - RoundedCornerRadius(context)
+			p.submenu.anchorBounds = image.Rect(lp.X, y, lp.X+lw, r.Max.Y)
+			guigui.SetPosition(p.submenu, image.Pt(lp.X+lw, y))
+		}
+		appender.AppendChildWidget(p.submenu)
+	}
+}
+
+// updateItems resolves the items' commands and updates the widths of the columns.
+func (p *PopupMenu) updateItems(context *guigui.Context) {
+	var selectabilityChanged bool
+	for i, item := range p.items {
+		w := p.itemWidgets[i]
+		text := item.Text
+		shortcut := item.Shortcut
+		var disabled bool
+		if item.Command != "" {
+			if command, ok := context.Command(item.Command); ok && text == "" {
+				text = command.Title
+			}
+			if shortcut == "" {
+				if chords := context.AppendKeyChordsForCommand(nil, item.Command); len(chords) > 0 {
+					shortcut = chords[0].String()
+				}
+			}
+			disabled = !context.IsCommandEnabled(item.Command)
+		}
+		w.text.SetText(text)
+		w.shortcut.SetText(shortcut)
+		if w.commandDisabled != disabled {
+			w.commandDisabled = disabled
+			selectabilityChanged = true
+		}
+	}
+
+	var c popupMenuColumns
+	for i, item := range p.items {
+		if item.Separator {
+			continue
+		}
+		if item.hasMark() {
+			c.mark = int(LineHeight(context))
+		}
+		if item.Icon != nil {
+			c.icon = int(LineHeight(context))
+		}
+		w, _ := p.itemWidgets[i].text.TextSize(context)
+		c.text = max(c.text, w)
+		if p.itemWidgets[i].shortcut.Text() != "" {
+			w, _ := p.itemWidgets[i].shortcut.TextSize(context)
+			c.shortcut = max(c.shortcut, w+UnitSize(context))
+		}
+		if len(item.Submenu) > 0 {
+			c.arrow = UnitSize(context)
+		}
+	}
+	if p.columns == c && !selectabilityChanged {
+		return
+	}
+	p.columns = c
+	// Reset the list's cached size.
+	p.list.SetItems(p.listItems())
 }
 
 func (p *PopupMenu) contentBounds(context *guigui.Context) image.Rectangle {
 	pos := guigui.Position(p)
-	w, h := p.textList.Size(context)
+	w, h := p.list.Size(context)
 	if h > 24*UnitSize(context) {
 		h = 24 * UnitSize(context)
-		p.textList.SetHeight(h)
+		p.list.SetHeight(h)
 	}
 	r := image.Rectangle{
 		Min: pos,
@@ -57,8 +257,14 @@ func (p *PopupMenu) contentBounds(context *guigui.Context) image.Rectangle {
 	}
 	aw, ah := context.AppSize()
 	if r.Max.X > aw {
-		r.Min.X = aw - w
-		r.Max.X = aw
+		if p.parent != nil {
+			// Put the submenu on the other side of the parent menu.
+			r.Min.X = p.anchorBounds.Min.X - w
+			r.Max.X = p.anchorBounds.Min.X
+		} else {
+			r.Min.X = aw - w
+			r.Max.X = aw
+		}
 	}
 	if r.Min.X < 0 {
 		r.Min.X = 0
@@ -81,25 +287,564 @@ func (p *PopupMenu) updateContentBounds(context *guigui.Context) {
 
 func (p *PopupMenu) Open(context *guigui.Context) {
 	p.updateContentBounds(context)
+	p.open(false)
+}
+
+// open opens the menu. If highlightFirst is true, the first selectable item is highlighted for the key operations.
+func (p *PopupMenu) open(highlightFirst bool) {
+	p.list.SetSelectedItemIndex(-1)
+	p.list.setHoveredItemIndex(-1)
+	p.list.resetCursorPosition()
+	p.keyHighlightedItemIndexPlus1 = 0
+	p.lastHoveredItemIndexPlus1 = 0
+	p.hoveringCount = 0
+	if highlightFirst {
+		p.setHighlightedItemIndex(p.nextSelectableItemIndex(-1, 1))
+	}
 	p.popup.Open()
 }
 
 func (p *PopupMenu) Close() {
+	p.closeSubmenu()
 	p.popup.Close()
+	if p.onClose != nil {
+		p.onClose()
+	}
+}
+
+func (p *PopupMenu) isOpen() bool {
+	return p.popup.isOpen()
+}
+
+func (p *PopupMenu) root() *PopupMenu {
+	m := p
+	for m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
+
+// SetItems sets the items. The items are copied.
+func (p *PopupMenu) SetItems(items []PopupMenuItem) {
+	if equalPopupMenuItems(p.items, items) {
+		return
+	}
+	p.setItems(clonePopupMenuItems(items))
+}
+
+// setItems sets the items without copying them.
+// A submenu shares the items with the parent menu so that the checked states are kept in the parent menu.
+func (p *PopupMenu) setItems(items []PopupMenuItem) {
+	changed := !equalPopupMenuItems(p.items, items)
+	p.items = items
+
+	if cap(p.itemWidgets) < len(items) {
+		p.itemWidgets = append(p.itemWidgets, make([]*popupMenuItemWidget, len(items)-cap(p.itemWidgets))...)
+	}
+	p.itemWidgets = p.itemWidgets[:len(items)]
+	for i := range items {
+		if p.itemWidgets[i] == nil {
+			p.itemWidgets[i] = &popupMenuItemWidget{}
+		}
+		w := p.itemWidgets[i]
+		w.menu = p
+		w.index = i
+	}
+	p.list.SetItems(p.listItems())
+
+	if idx := p.submenuItemIndexPlus1 - 1; idx >= 0 {
+		if idx < len(items) && len(items[idx].Submenu) > 0 && items[idx].selectable() {
+			p.submenu.setItems(items[idx].Submenu)
+		} else {
+			p.closeSubmenu()
+		}
+	}
+
+	if changed {
+		guigui.RequestRelayout(p)
+	}
+}
+
+func (p *PopupMenu) listItems() []ListItem {
+	listItems := make([]ListItem, len(p.items))
+	for i := range p.items {
+		listItems[i] = ListItem{
+			Content:    p.itemWidgets[i],
+			Selectable: p.isItemSelectable(i),
+		}
+	}
+	return listItems
+}
+
+// isItemSelectable reports whether the item can be chosen. An item is not selectable while its command is disabled.
+func (p *PopupMenu) isItemSelectable(index int) bool {
+	return p.items[index].selectable() && !p.itemWidgets[index].commandDisabled
 }
 
 func (p *PopupMenu) SetItemsByStrings(items []string) {
-	p.textList.SetItemsByStrings(items)
+	menuItems := make([]PopupMenuItem, len(items))
+	for i, str := range items {
+		menuItems[i].Text = str
+	}
+	p.setItems(menuItems)
+}
+
+// IsItemChecked reports whether the item of the ID in the menu or its submenus is checked.
+func (p *PopupMenu) IsItemChecked(id string) bool {
+	item, _ := findPopupMenuItem(p.items, id)
+	return item != nil && item.Checked
+}
+
+// SetItemChecked checks or unchecks the item of the ID in the menu or its submenus.
+// Checking a radio item unchecks the other items of the same group.
+func (p *PopupMenu) SetItemChecked(id string, checked bool) {
+	item, siblings := findPopupMenuItem(p.items, id)
+	if item == nil {
+		return
+	}
+	if checked && item.RadioGroup != "" {
+		uncheckRadioGroup(siblings, item.RadioGroup)
+	}
+	item.Checked = checked
+	guigui.RequestRedraw(p)
+}
+
+func findPopupMenuItem(items []PopupMenuItem, id string) (item *PopupMenuItem, siblings []PopupMenuItem) {
+	for i := range items {
+		if items[i].ID == id && !items[i].Separator {
+			return &items[i], items
+		}
+		if item, siblings := findPopupMenuItem(items[i].Submenu, id); item != nil {
+			return item, siblings
+		}
+	}
+	return nil, nil
+}
+
+func uncheckRadioGroup(items []PopupMenuItem, group string) {
+	for i := range items {
+		if items[i].RadioGroup == group {
+			items[i].Checked = false
+		}
+	}
 }
 
+// SelectedItem returns the item chosen last time as a TextListItem.
+//
+// Use SelectedPopupMenuItem to get the item including the menu-specific fields.
 func (p *PopupMenu) SelectedItem() (TextListItem, bool) {
-	return p.textList.SelectedItem()
+	item, ok := p.SelectedPopupMenuItem()
+	if !ok {
+		return TextListItem{}, false
+	}
+	return TextListItem{
+		Text:     item.Text,
+		Disabled: item.Disabled,
+		Border:   item.Separator,
+	}, true
+}
+
+// SelectedPopupMenuItem returns the item chosen last time.
+func (p *PopupMenu) SelectedPopupMenuItem() (PopupMenuItem, bool) {
+	idx := p.SelectedItemIndex()
+	if idx < 0 || idx >= len(p.items) {
+		return PopupMenuItem{}, false
+	}
+	return p.items[idx], true
 }
 
+// SelectedItemIndex returns the index of the item chosen last time.
 func (p *PopupMenu) SelectedItemIndex() int {
-	return p.textList.SelectedItemIndex()
+	return p.selectedItemIndexPlus1 - 1
 }
 
 func (p *PopupMenu) SetSelectedItemIndex(index int) {
-	p.textList.SetSelectedItemIndex(index)
+	if index < 0 || index >= len(p.items) {
+		index = -1
+	}
+	if p.selectedItemIndexPlus1 == index+1 {
+		return
+	}
+	p.selectedItemIndexPlus1 = index + 1
+	guigui.RequestRedraw(p)
+}
+
+// activate chooses the item. If the item has a submenu, the submenu is opened.
+func (p *PopupMenu) activate(context *guigui.Context, index int, byKey bool) {
+	if !p.isItemSelectable(index) {
+		return
+	}
+	item := &p.items[index]
+	if len(item.Submenu) > 0 {
+		p.openSubmenu(context, index, byKey)
+		return
+	}
+
+	if item.Checkable {
+		item.Checked = !item.Checked
+	}
+	if item.RadioGroup != "" {
+		uncheckRadioGroup(p.items, item.RadioGroup)
+		item.Checked = true
+	}
+
+	id := item.ID
+	command := item.Command
+	root := p.root()
+	root.Close()
+	if p == root {
+		p.selectedItemIndexPlus1 = index + 1
+		if p.onClosed != nil {
+			p.onClosed(index)
+		}
+	}
+	if command != "" {
+		context.ExecuteCommand(command)
+	}
+	if root.onItemSelected != nil {
+		root.onItemSelected(id)
+	}
+}
+
+func (p *PopupMenu) openSubmenu(context *guigui.Context, index int, highlightFirst bool) {
+	if p.submenu != nil && p.submenuItemIndexPlus1-1 == index && p.submenu.isOpen() {
+		if highlightFirst {
+			p.submenu.setHighlightedItemIndex(p.submenu.nextSelectableItemIndex(-1, 1))
+		}
+		return
+	}
+	p.closeSubmenu()
+
+	if p.submenu == nil {
+		p.submenu = &PopupMenu{
+			parent: p,
+		}
+	}
+	p.submenuItemIndexPlus1 = index + 1
+	p.submenu.setItems(p.items[index].Submenu)
+	p.submenu.open(highlightFirst)
+	// The submenu might not be a child yet.
+	guigui.RequestRelayout(p)
+}
+
+func (p *PopupMenu) closeSubmenu() {
+	if p.submenuItemIndexPlus1 == 0 {
+		return
+	}
+	if p.submenu != nil {
+		p.submenu.Close()
+	}
+	p.submenuItemIndexPlus1 = 0
+	guigui.RequestRelayout(p)
+}
+
+func (p *PopupMenu) highlightedItemIndex() int {
+	return p.list.HoveredItemIndex()
+}
+
+func (p *PopupMenu) setHighlightedItemIndex(index int) {
+	p.list.setHoveredItemIndex(index)
+	p.keyHighlightedItemIndexPlus1 = index + 1
+}
+
+// nextSelectableItemIndex returns the index of the next selectable item from the index in the direction.
+// The search wraps around. If there is no selectable item, nextSelectableItemIndex returns -1.
+func (p *PopupMenu) nextSelectableItemIndex(from int, direction int) int {
+	n := len(p.items)
+	if from < 0 && direction < 0 {
+		from = n
+	}
+	for i := 1; i <= n; i++ {
+		idx := ((from+direction*i)%n + n) % n
+		if p.isItemSelectable(idx) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// HandleInput handles the keys to operate the menu.
+//
+// HandleInput is called by the popups of the menu and its submenus, as the popups are above the menu and abort the inputs to the widgets behind them.
+func (p *PopupMenu) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	// Only the innermost open menu handles keys. The root menu dispatches the keys so that submenus don't handle the same keys.
+	if p.parent != nil || !p.isOpen() {
+		return guigui.HandleInputResult{}
+	}
+	m := p
+	for m.submenuItemIndexPlus1 > 0 {
+		m = m.submenu
+	}
+	if !m.handleKeyInput(context) {
+		return guigui.HandleInputResult{}
+	}
+	return guigui.HandleInputByWidget(p)
+}
+
+func (p *PopupMenu) Update(context *guigui.Context) error {
+	if !p.isOpen() {
+		// The menu might be closed by clicking outside.
+		p.closeSubmenu()
+		return nil
+	}
+
+	p.updateSubmenuByHovering(context)
+	return nil
+}
+
+// handleKeyInput handles the keys, and reports whether a key is handled.
+func (p *PopupMenu) handleKeyInput(context *guigui.Context) bool {
+	input := context.Input()
+	idx := p.highlightedItemIndex()
+	switch {
+	case isKeyRepeating(input, ebiten.KeyDown):
+		p.setHighlightedItemIndex(p.nextSelectableItemIndex(idx, 1))
+	case isKeyRepeating(input, ebiten.KeyUp):
+		p.setHighlightedItemIndex(p.nextSelectableItemIndex(idx, -1))
+	case input.IsKeyJustPressed(ebiten.KeyHome):
+		p.setHighlightedItemIndex(p.nextSelectableItemIndex(-1, 1))
+	case input.IsKeyJustPressed(ebiten.KeyEnd):
+		p.setHighlightedItemIndex(p.nextSelectableItemIndex(-1, -1))
+	case input.IsKeyJustPressed(ebiten.KeyRight):
+		if idx >= 0 && len(p.items[idx].Submenu) > 0 {
+			p.activate(context, idx, true)
+		} else if root := p.root(); root.onAdjacentMenu != nil {
+			root.onAdjacentMenu(1)
+		}
+	case input.IsKeyJustPressed(ebiten.KeyEnter) || input.IsKeyJustPressed(ebiten.KeyNumpadEnter) || input.IsKeyJustPressed(ebiten.KeySpace):
+		if idx >= 0 {
+			p.activate(context, idx, true)
+		}
+	case input.IsKeyJustPressed(ebiten.KeyLeft):
+		if p.parent != nil {
+			p.parent.closeSubmenu()
+		} else if p.onAdjacentMenu != nil {
+			p.onAdjacentMenu(-1)
+		}
+	case input.IsKeyJustPressed(ebiten.KeyEscape):
+		if p.parent != nil {
+			p.parent.closeSubmenu()
+		} else {
+			p.Close()
+		}
+	default:
+		return false
+	}
+
+	// Scroll the menu to show the item highlighted by keys.
+	if idx := p.keyHighlightedItemIndexPlus1 - 1; idx >= 0 && idx < len(p.items) {
+		if !p.list.itemRect(context, idx).In(guigui.VisibleBounds(&p.list)) {
+			p.list.JumpToItemIndex(idx)
+		}
+	}
+	return true
+}
+
+// updateSubmenuByHovering opens or closes the submenu when the cursor stays on an item for a while.
+func (p *PopupMenu) updateSubmenuByHovering(context *guigui.Context) {
+	idx := p.highlightedItemIndex()
+	if idx < 0 && p.submenuItemIndexPlus1 > 0 {
+		// Keep the item of the open submenu highlighted while the cursor is out of the menu.
+		idx = p.submenuItemIndexPlus1 - 1
+		p.list.setHoveredItemIndex(idx)
+	}
+
+	if idx != p.lastHoveredItemIndexPlus1-1 {
+		p.lastHoveredItemIndexPlus1 = idx + 1
+		p.hoveringCount = 0
+		if idx != p.keyHighlightedItemIndexPlus1-1 {
+			p.keyHighlightedItemIndexPlus1 = 0
+		}
+		return
+	}
+	// An item highlighted by keys opens the submenu only by keys.
+	if idx < 0 || idx == p.keyHighlightedItemIndexPlus1-1 {
+		return
+	}
+	p.hoveringCount++
+	if p.hoveringCount != submenuDelay() {
+		return
+	}
+	if idx == p.submenuItemIndexPlus1-1 {
+		return
+	}
+	p.closeSubmenu()
+	if len(p.items[idx].Submenu) > 0 && p.isItemSelectable(idx) {
+		p.openSubmenu(context, idx, false)
+	}
+}
+
+type popupMenuItemWidget struct {
+	guigui.DefaultWidget
+
+	menu  *PopupMenu
+	index int
+
+	icon     Image
+	text     Text
+	shortcut Text
+
+	commandDisabled bool
+}
+
+func (p *popupMenuItemWidget) item() *PopupMenuItem {
+	return &p.menu.items[p.index]
+}
+
+func (p *popupMenuItemWidget) selectable() bool {
+	return p.menu.isItemSelectable(p.index)
+}
+
+func (p *popupMenuItemWidget) isHighlighted() bool {
+	return p.menu.highlightedItemIndex() == p.index && p.selectable()
+}
+
+func (p *popupMenuItemWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	item := p.item()
+	if item.Separator {
+		return
+	}
+
+	c := p.menu.columns
+	pos := guigui.Position(p)
+	h := int(LineHeight(context))
+
+	if item.Icon != nil {
+		// Leave a margin around the icon.
+		s := h * 3 / 4
+		p.icon.SetImage(item.Icon)
+		p.icon.SetSize(context, s, s)
+		if !p.selectable() {
+			guigui.SetOpacity(&p.icon, 0.25)
+		} else {
+			guigui.SetOpacity(&p.icon, 1)
+		}
+		guigui.SetPosition(&p.icon, pos.Add(image.Pt(c.mark+(c.icon-s)/2, (h-s)/2)))
+		appender.AppendChildWidget(&p.icon)
+	}
+
+	// The texts are set by PopupMenu.
+	p.text.SetVerticalAlign(VerticalAlignMiddle)
+	guigui.SetPosition(&p.text, pos.Add(image.Pt(c.mark+c.icon, 0)))
+	appender.AppendChildWidget(&p.text)
+
+	if p.shortcut.Text() != "" {
+		p.shortcut.SetVerticalAlign(VerticalAlignMiddle)
+		p.shortcut.SetHorizontalAlign(HorizontalAlignEnd)
+		p.shortcut.SetSize(c.shortcut, h)
+		guigui.SetPosition(&p.shortcut, pos.Add(image.Pt(c.mark+c.icon+c.text, 0)))
+		appender.AppendChildWidget(&p.shortcut)
+	}
+}
+
+func (p *popupMenuItemWidget) Update(context *guigui.Context) error {
+	switch {
+	case !p.selectable():
+		p.text.SetColor(DefaultDisabledListItemTextColor(context))
+		p.shortcut.SetColor(DefaultDisabledListItemTextColor(context))
+	case p.isHighlighted():
+		p.text.SetColor(DefaultActiveListItemTextColor(context))
+		p.shortcut.SetColor(DefaultActiveListItemTextColor(context))
+	default:
+		p.text.SetColor(nil)
+		p.shortcut.SetColor(Color(context.ColorMode(), ColorTypeBase, 0.5))
+	}
+	return nil
+}
+
+func (p *popupMenuItemWidget) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(p)
+	item := p.item()
+
+	if item.Separator {
+		y := float32(bounds.Min.Y) + float32(bounds.Dy())/2
+		width := float32(1 * context.Scale())
+		vector.StrokeLine(dst, float32(bounds.Min.X), y, float32(bounds.Max.X), y, width, Color(context.ColorMode(), ColorTypeBase, 0.8), false)
+		return
+	}
+
+	clr := DefaultTextColor(context)
+	switch {
+	case !p.selectable():
+		clr = DefaultDisabledListItemTextColor(context)
+	case p.isHighlighted():
+		clr = DefaultActiveListItemTextColor(context)
+	}
+	c := p.menu.columns
+	h := float32(bounds.Dy())
+	strokeWidth := float32(1.5 * context.Scale())
+
+	if item.Checked && c.mark > 0 {
+		cx := float32(bounds.Min.X) + float32(c.mark)/2
+		cy := float32(bounds.Min.Y) + h/2
+		if item.RadioGroup != "" {
+			vector.DrawFilledCircle(dst, cx, cy, h/8, clr, true)
+		} else {
+			s := h / 4
+			var path vector.Path
+			path.MoveTo(cx-s, cy)
+			path.LineTo(cx-s/3, cy+s*2/3)
+			path.LineTo(cx+s, cy-s*2/3)
+			vector.StrokePath(dst, &path, clr, true, &vector.StrokeOptions{
+				Width:    strokeWidth,
+				LineJoin: vector.LineJoinRound,
+				LineCap:  vector.LineCapRound,
+			})
+		}
+	}
+
+	if len(item.Submenu) > 0 {
+		// Draw a chevron at the end.
+		cx := float32(bounds.Min.X+c.width()) - float32(c.arrow)/2
+		cy := float32(bounds.Min.Y) + h/2
+		s := h / 8
+		var path vector.Path
+		path.MoveTo(cx-s/2, cy-s)
+		path.LineTo(cx+s/2, cy)
+		path.LineTo(cx-s/2, cy+s)
+		vector.StrokePath(dst, &path, clr, true, &vector.StrokeOptions{
+			Width:    strokeWidth,
+			LineJoin: vector.LineJoinRound,
+			LineCap:  vector.LineCapRound,
+		})
+	}
+}
+
+func (p *popupMenuItemWidget) Size(context *guigui.Context) (int, int) {
+	w := p.menu.columns.width()
+	if p.item().Separator {
+		return w, UnitSize(context) / 2
+	}
+	return w, int(LineHeight(context))
+}
+
+func (p *popupMenuItemWidget) Accessibility(context *guigui.Context) guigui.Accessibility {
+	item := p.item()
+	if item.Separator {
+		return guigui.Accessibility{
+			Role: guigui.AccessibilityRoleSeparator,
+			Leaf: true,
+		}
+	}
+	a := guigui.Accessibility{
+		Role:     guigui.AccessibilityRoleMenuItem,
+		Label:    p.text.Text(),
+		Value:    p.shortcut.Text(),
+		Checked:  item.Checked,
+		Expanded: len(item.Submenu) > 0 && p.menu.submenuItemIndexPlus1-1 == p.index,
+		Disabled: !p.selectable(),
+		Leaf:     true,
+	}
+	if p.selectable() {
+		a.Actions = []guigui.AccessibilityAction{guigui.AccessibilityActionPress}
+	}
+	return a
+}
+
+func (p *popupMenuItemWidget) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress || !p.selectable() {
+		return false
+	}
+	p.menu.activate(context, p.index, true)
+	return true
 }
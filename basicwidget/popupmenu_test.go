@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func TestPopupMenuConsumesKeys(t *testing.T) {
+	var button Button
+	var upCount int
+	button.SetOnUp(func() {
+		upCount++
+	})
+	var menu PopupMenu
+	menu.SetItemsByStrings([]string{"Foo", "Bar"})
+	closedIndex := -1
+	menu.SetOnClosed(func(index int) {
+		closedIndex = index
+	})
+	d := newTestDriver(&button, &menu)
+
+	var commandCount int
+	if err := d.Context().RegisterCommand(guigui.Command{
+		Name: "down",
+		Action: func() {
+			commandCount++
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Context().BindKey(guigui.KeyChord{Key: ebiten.KeyDown}, "down"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	guigui.Focus(&button)
+	menu.Open(d.Context())
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keys are handled by the menu even while the menu is being shown, and are not dispatched to the command.
+	pressKeys(t, d, 2, ebiten.KeyDown)
+	pressKeys(t, d, 2, ebiten.KeyDown)
+	if got, want := menu.highlightedItemIndex(), 1; got != want {
+		t.Errorf("highlightedItemIndex(): got: %d, want: %d", got, want)
+	}
+	if got, want := commandCount, 0; got != want {
+		t.Errorf("commandCount: got: %d, want: %d", got, want)
+	}
+
+	// Enter chooses the item instead of pressing the focused button behind the menu.
+	pressKeys(t, d, 1, ebiten.KeyEnter)
+	if got, want := closedIndex, 1; got != want {
+		t.Errorf("closedIndex: got: %d, want: %d", got, want)
+	}
+	if got, want := upCount, 0; got != want {
+		t.Errorf("upCount: got: %d, want: %d", got, want)
+	}
+	if menu.isOpen() {
+		t.Errorf("isOpen(): got: true, want: false")
+	}
+}
+
+func TestPopupMenuOpensSubmenuAtFirstTime(t *testing.T) {
+	var menu PopupMenu
+	menu.SetItems([]PopupMenuItem{
+		{
+			Text: "Foo",
+			Submenu: []PopupMenuItem{
+				{Text: "Bar"},
+				{Text: "Baz"},
+			},
+		},
+	})
+	d := newTestDriver(&menu)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	menu.Open(d.Context())
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressKeys(t, d, 2, ebiten.KeyDown)
+	pressKeys(t, d, 2, ebiten.KeyRight)
+	if menu.submenu == nil || !guigui.IsVisible(&menu.submenu.popup) {
+		t.Fatalf("the submenu is not visible after the first open")
+	}
+	if got, want := menu.submenu.highlightedItemIndex(), 0; got != want {
+		t.Errorf("submenu.highlightedItemIndex(): got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"golang.org/x/text/language"
+
+	"github.com/xackery/guigui"
+	"github.com/xackery/guigui/guiguitest"
+)
+
+// testRoot is a root widget arranging the widgets vertically.
+type testRoot struct {
+	guigui.RootWidget
+
+	widgets []guigui.Widget
+}
+
+func (t *testRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	p := guigui.Position(t)
+	for _, w := range t.widgets {
+		guigui.SetPosition(w, p)
+		appender.AppendChildWidget(w)
+		_, h := w.Size(context)
+		p.Y += h
+	}
+}
+
+// newTestDriver returns a driver for a testRoot with the widgets.
+// The locale is fixed so that the fonts don't depend on the environment.
+func newTestDriver(widgets ...guigui.Widget) *guiguitest.Driver {
+	d := guiguitest.New(&testRoot{widgets: widgets}, 320, 240, 1)
+	d.Context().SetAppLocales([]language.Tag{language.English})
+	return d
+}
+
+// findAccessibilityNode returns the first node in the depth-first order satisfying f.
+func findAccessibilityNode(node guigui.AccessibilityNode, f func(node *guigui.AccessibilityNode) bool) (guigui.AccessibilityNode, bool) {
+	if f(&node) {
+		return node, true
+	}
+	for _, child := range node.Children {
+		if n, ok := findAccessibilityNode(child, f); ok {
+			return n, true
+		}
+	}
+	return guigui.AccessibilityNode{}, false
+}
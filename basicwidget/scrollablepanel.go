@@ -29,8 +29,12 @@ func (s *ScrollablePanel) SetContent(f func(context *guigui.Context, childAppend
 }
 
 func (s *ScrollablePanel) SetPadding(paddingX, paddingY int) {
+	if s.paddingX == paddingX && s.paddingY == paddingY {
+		return
+	}
 	s.paddingX = paddingX
 	s.paddingY = paddingY
+	guigui.RequestRelayout(s)
 }
 
 func (s *ScrollablePanel) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
@@ -77,8 +81,12 @@ func (s *ScrollablePanel) Size(context *guigui.Context) (int, int) {
 
 func (s *ScrollablePanel) SetSize(context *guigui.Context, width, height int) {
 	dw, dh := defaultScrollablePanelSize(context)
+	if s.widthMinusDefault == width-dw && s.heightMinusDefault == height-dh {
+		return
+	}
 	s.widthMinusDefault = width - dw
 	s.heightMinusDefault = height - dh
+	guigui.RequestRelayout(s)
 }
 
 type scrollablePanelBorder struct {
@@ -9,7 +9,6 @@ import (
 	"runtime"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/xackery/guigui"
 )
 
@@ -57,8 +56,14 @@ func (s *ScrollOverlay) SetOnScroll(f func(offsetX, offsetY float64)) {
 }
 
 func (s *ScrollOverlay) Reset() {
+	if s.offsetX == 0 && s.offsetY == 0 {
+		return
+	}
 	s.offsetX = 0
 	s.offsetY = 0
+	guigui.RequestRedraw(s)
+	// The parent lays out the content by the offset.
+	guigui.RequestRelayout(s)
 }
 
 func (s *ScrollOverlay) SetContentSize(contentWidth, contentHeight int) {
@@ -68,11 +73,16 @@ func (s *ScrollOverlay) SetContentSize(contentWidth, contentHeight int) {
 
 	s.contentWidth = contentWidth
 	s.contentHeight = contentHeight
+	prevOffsetX := s.offsetX
+	prevOffsetY := s.offsetY
 	s.adjustOffset()
 	if s.onceRendered {
 		s.contentSizeChanged = true
 		guigui.RequestRedraw(s)
 	}
+	if prevOffsetX != s.offsetX || prevOffsetY != s.offsetY {
+		guigui.RequestRelayout(s)
+	}
 }
 
 func (s *ScrollOverlay) SetOffsetByDelta(dx, dy float64) {
@@ -89,6 +99,7 @@ func (s *ScrollOverlay) SetOffset(x, y float64) {
 	if s.onceRendered {
 		guigui.RequestRedraw(s)
 	}
+	guigui.RequestRelayout(s)
 }
 
 func (s *ScrollOverlay) setHovering(hovering bool) {
@@ -104,8 +115,8 @@ func (s *ScrollOverlay) setDragging(draggingX, draggingY bool) {
 	s.draggingY = draggingY
 }
 
-func adjustedWheel() (float64, float64) {
-	x, y := ebiten.Wheel()
+func adjustedWheel(input *guigui.InputState) (float64, float64) {
+	x, y := input.Wheel()
 	switch runtime.GOOS {
 	case "darwin":
 		x *= 2
@@ -115,11 +126,13 @@ func adjustedWheel() (float64, float64) {
 }
 
 func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputResult {
-	s.setHovering(image.Pt(ebiten.CursorPosition()).In(guigui.VisibleBounds(s)) && guigui.IsVisible(s))
+	input := context.Input()
+	s.setHovering(input.CursorPosition().In(guigui.VisibleBounds(s)) && guigui.IsVisible(s))
 
 	if s.hovering {
-		x, y := ebiten.CursorPosition()
-		dx, dy := adjustedWheel()
+		cp := input.CursorPosition()
+		x, y := cp.X, cp.Y
+		dx, dy := adjustedWheel(input)
 		s.lastCursorX = x
 		s.lastCursorY = y
 		s.lastWheelX = dx
@@ -131,8 +144,9 @@ func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputR
 		s.lastWheelY = 0
 	}
 
-	if !s.draggingX && !s.draggingY && s.hovering && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
+	if !s.draggingX && !s.draggingY && s.hovering && input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		cp := input.CursorPosition()
+		x, y := cp.X, cp.Y
 		hb, vb := s.barBounds(context)
 		if image.Pt(x, y).In(hb) {
 			s.setDragging(true, s.draggingY)
@@ -148,12 +162,13 @@ func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputR
 		}
 	}
 
-	if dx, dy := adjustedWheel(); dx != 0 || dy != 0 {
+	if dx, dy := adjustedWheel(input); dx != 0 || dy != 0 {
 		s.setDragging(false, false)
 	}
 
-	if (s.draggingX || s.draggingY) && ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
+	if (s.draggingX || s.draggingY) && input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		cp := input.CursorPosition()
+		x, y := cp.X, cp.Y
 		var dx, dy float64
 		if s.draggingX {
 			dx = float64(x - s.draggingStartX)
@@ -181,16 +196,17 @@ func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputR
 					s.onScroll(s.offsetX, s.offsetY)
 				}
 				guigui.RequestRedraw(s)
+				guigui.RequestRelayout(s)
 			}
 		}
 		return guigui.HandleInputByWidget(s)
 	}
 
-	if (s.draggingX || s.draggingY) && !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+	if (s.draggingX || s.draggingY) && !input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 		s.setDragging(false, false)
 	}
 
-	if dx, dy := adjustedWheel(); dx != 0 || dy != 0 {
+	if dx, dy := adjustedWheel(input); dx != 0 || dy != 0 {
 		if !s.hovering {
 			return guigui.HandleInputResult{}
 		}
@@ -206,6 +222,7 @@ func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputR
 				s.onScroll(s.offsetX, s.offsetY)
 			}
 			guigui.RequestRedraw(s)
+			guigui.RequestRelayout(s)
 			return guigui.HandleInputByWidget(s)
 		}
 		return guigui.HandleInputResult{}
@@ -215,9 +232,9 @@ func (s *ScrollOverlay) HandleInput(context *guigui.Context) guigui.HandleInputR
 }
 
 func (s *ScrollOverlay) CursorShape(context *guigui.Context) (ebiten.CursorShapeType, bool) {
-	x, y := ebiten.CursorPosition()
+	p := context.Input().CursorPosition()
 	hb, vb := s.barBounds(context)
-	if image.Pt(x, y).In(hb) || image.Pt(x, y).In(vb) {
+	if p.In(hb) || p.In(vb) {
 		return ebiten.CursorShapeDefault, true
 	}
 	return 0, false
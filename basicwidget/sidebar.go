@@ -47,6 +47,10 @@ func (s *Sidebar) Size(context *guigui.Context) (int, int) {
 
 func (s *Sidebar) SetSize(context *guigui.Context, width, height int) {
 	dw, dh := defaultSidebarWidth(context)
+	if s.widthMinusDefault == width-dw && s.heightMinusDefault == height-dh {
+		return
+	}
 	s.widthMinusDefault = width - dw
 	s.heightMinusDefault = height - dh
+	guigui.RequestRelayout(s)
 }
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"slices"
+
+	"github.com/xackery/guigui"
+)
+
+// Stack arranges widgets vertically or horizontally with a gap.
+//
+// The widgets in a Stack must not be appended by other widgets.
+type Stack struct {
+	guigui.DefaultWidget
+
+	items  []FlexItem
+	layout flexLayout
+}
+
+// SetWidgets sets the widgets to arrange.
+func (s *Stack) SetWidgets(widgets []guigui.Widget) {
+	if slices.EqualFunc(s.items, widgets, func(item FlexItem, widget guigui.Widget) bool {
+		return item.Widget == widget
+	}) {
+		return
+	}
+	s.items = slices.Delete(s.items, 0, len(s.items))
+	for _, w := range widgets {
+		s.items = append(s.items, FlexItem{
+			Widget: w,
+		})
+	}
+	guigui.RequestRelayout(s)
+}
+
+// SetDirection sets the direction to arrange the widgets. The default is vertical.
+func (s *Stack) SetDirection(direction LayoutDirection) {
+	if s.layout.direction == direction {
+		return
+	}
+	s.layout.direction = direction
+	guigui.RequestRelayout(s)
+}
+
+// SetGap sets the space between the widgets.
+func (s *Stack) SetGap(gap int) {
+	if s.layout.gap == gap {
+		return
+	}
+	s.layout.gap = gap
+	guigui.RequestRelayout(s)
+}
+
+// SetAlignment sets the alignment of the widgets across the direction.
+// For example, LayoutAlignmentCenter centers the widgets horizontally in a vertical Stack.
+func (s *Stack) SetAlignment(alignment LayoutAlignment) {
+	if s.layout.alignment == alignment {
+		return
+	}
+	s.layout.alignment = alignment
+	guigui.RequestRelayout(s)
+}
+
+// SetSize sets the size. Without a size, the Stack is as large as its widgets.
+func (s *Stack) SetSize(context *guigui.Context, width, height int) {
+	if s.layout.setSize(width, height) {
+		guigui.RequestRelayout(s)
+	}
+}
+
+// ResetSize resets the size set by SetSize.
+func (s *Stack) ResetSize() {
+	if s.layout.resetSize() {
+		guigui.RequestRelayout(s)
+	}
+}
+
+func (s *Stack) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	s.layout.layout(context, guigui.Bounds(s), s.items, appender)
+}
+
+// Measure implements guigui.Measurer. The sizes are by the widgets, and don't depend on the size set by SetSize.
+func (s *Stack) Measure(context *guigui.Context, constraints guigui.Constraints) guigui.Measurement {
+	return s.layout.measure(context, s.items, constraints)
+}
+
+func (s *Stack) Size(context *guigui.Context) (int, int) {
+	return s.layout.size(context, s)
+}
@@ -7,14 +7,16 @@ import (
 	"image"
 	"image/color"
 	"log/slog"
+	"math"
 	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/exp/textinput"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/text/language"
@@ -23,8 +25,8 @@ import (
 	"github.com/xackery/guigui/internal/clipboard"
 )
 
-func isKeyRepeating(key ebiten.Key) bool {
-	d := inpututil.KeyPressDuration(key)
+func isKeyRepeating(input *guigui.InputState, key ebiten.Key) bool {
+	d := input.KeyPressDuration(key)
 	// In the current implementation of text, d == 1 might be skipped especially for backspace key.
 	// TODO: Fix this.
 	if d == 2 {
@@ -36,8 +38,42 @@ func isKeyRepeating(key ebiten.Key) bool {
 	return (d-24)%4 == 0
 }
 
+// isShortcutKeyRepeating is like isKeyRepeating, but is true at the first tick.
+// A shortcut with modifiers is handled at the first tick so that the same chord is not dispatched to commands.
+func isShortcutKeyRepeating(input *guigui.InputState, key ebiten.Key) bool {
+	d := input.KeyPressDuration(key)
+	if d == 1 {
+		return true
+	}
+	if d < 24 {
+		return false
+	}
+	return (d-24)%4 == 0
+}
+
+// textKeys are the keys the text handles by isKeyRepeating.
+// These keys are claimed at the first tick so that they are not dispatched to commands before the text handles them.
+var textKeys = []ebiten.Key{
+	ebiten.KeyBackspace,
+	ebiten.KeyLeft,
+	ebiten.KeyRight,
+	ebiten.KeyUp,
+	ebiten.KeyDown,
+	ebiten.KeyHome,
+	ebiten.KeyEnd,
+	ebiten.KeyPageUp,
+	ebiten.KeyPageDown,
+}
+
 type TextFilter func(text string, start, end int) (string, int, int)
 
+type TextLengthUnit int
+
+const (
+	TextLengthUnitGrapheme TextLengthUnit = iota
+	TextLengthUnitRune
+)
+
 func DefaultTextColor(context *guigui.Context) color.Color {
 	return Color(context.ColorMode(), ColorTypeBase, 0.1)
 }
@@ -63,24 +99,54 @@ type Text struct {
 	selectable           bool
 	editable             bool
 	multiline            bool
+	autoWrap             bool
+	truncation           TextTruncation
+	masked               bool
 	selectionDragStart   int
 	selectionShiftIndex  int
 	dragging             bool
 	toAdjustScrollOffset bool
-	prevFocused          bool
 
-	filter TextFilter
+	lastClickTime  time.Time
+	lastClickIndex int
+	clickCount     int
+
+	filter        TextFilter
+	maxLength     int
+	maxLengthUnit TextLengthUnit
 
 	cursor        textCursor
 	scrollOverlay ScrollOverlay
 
 	temporaryClipboard string
 
+	history textHistory
+
+	maskedTextCache textMask
+	wrapCache       textWrap
+	truncationCache textTruncationCache
+
+	tooltip Tooltip
+
+	contextMenu   *contextMenu
+	onContextMenu func(position image.Point) []ContextMenuItem
+
+	spans               []textSpan
+	styledText          string
+	styledLinesCacheKey styledLinesCacheKey
+	styledLinesCache    []styledLine
+	pressedLink         string
+
+	// keyHandler handles key inputs before the text does, and reports whether the inputs are handled.
+	// keyHandler is used by a widget composing the text, like NumberInput.
+	keyHandler func(context *guigui.Context) bool
+
 	cachedWidth  int
 	cachedHeight int
 	initOnce     sync.Once
 
 	onEnterPressed func(text string)
+	onLinkClicked  func(link string)
 }
 
 func (t *Text) SetOnEnterPressed(f func(text string)) {
@@ -90,6 +156,7 @@ func (t *Text) SetOnEnterPressed(f func(text string)) {
 func (t *Text) resetCachedSize() {
 	t.cachedWidth = -1
 	t.cachedHeight = -1
+	guigui.RequestRelayout(t)
 }
 
 func (t *Text) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
@@ -97,6 +164,8 @@ func (t *Text) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppen
 		t.resetCachedSize()
 	})
 
+	guigui.SetFocusable(t, t.selectable || t.editable)
+
 	if t.selectable || t.editable {
 		p := guigui.Position(t)
 		p.X -= cursorWidth(context)
@@ -106,6 +175,17 @@ func (t *Text) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppen
 
 	guigui.SetPosition(&t.scrollOverlay, guigui.Position(t))
 	appender.AppendChildWidget(&t.scrollOverlay)
+
+	// Show the entire text when the truncated text is hovered.
+	if t.IsTruncated(context) {
+		t.tooltip.SetText(t.unwrappedTextToDraw())
+		guigui.SetPosition(&t.tooltip, guigui.Position(t))
+		appender.AppendChildWidget(&t.tooltip)
+	}
+
+	if t.contextMenu != nil {
+		appender.AppendChildWidget(&t.contextMenu.popupMenu)
+	}
 }
 
 func (t *Text) SetSelectable(selectable bool) {
@@ -116,18 +196,30 @@ func (t *Text) SetSelectable(selectable bool) {
 	t.selectionDragStart = -1
 	t.selectionShiftIndex = -1
 	guigui.RequestRedraw(t)
+	// The cursor and the focusability depend on the selectability.
+	guigui.RequestRelayout(t)
 }
 
 func (t *Text) Text() string {
 	return t.field.Text()
 }
 
+// SetText sets the text.
+//
+// SetText resets the edit history if the text is changed.
 func (t *Text) SetText(text string) {
+	if t.field.Text() != text {
+		t.history.reset()
+	}
 	start, end := t.field.Selection()
 	start = min(start, len(text))
 	end = min(end, len(text))
 	t.setTextAndSelection(text, start, end, -1)
-	t.resetCachedSize()
+	t.enforceMaxLength(textEditState{})
+	if t.spans != nil {
+		t.spans = nil
+		t.resetCachedSize()
+	}
 }
 
 func (t *Text) SetFilter(filter TextFilter) {
@@ -135,20 +227,138 @@ func (t *Text) SetFilter(filter TextFilter) {
 	t.applyFilter()
 }
 
+// SetMaxLength sets the maximum length of the text in the unit.
+// Inputs exceeding the maximum length are truncated. If maxLength is 0 or less, the length is not limited.
+func (t *Text) SetMaxLength(maxLength int, unit TextLengthUnit) {
+	if t.maxLength == maxLength && t.maxLengthUnit == unit {
+		return
+	}
+	t.maxLength = maxLength
+	t.maxLengthUnit = unit
+	t.enforceMaxLength(textEditState{})
+}
+
+// enforceMaxLength truncates the text inserted after the state before so that the text doesn't exceed the maximum length.
+func (t *Text) enforceMaxLength(before textEditState) {
+	if t.maxLength <= 0 {
+		return
+	}
+	after := t.field.Text()
+	if textLength(after, t.maxLengthUnit) <= t.maxLength {
+		return
+	}
+
+	// Find the inserted part by the common prefix and suffix.
+	var prefix int
+	for prefix < len(before.text) && prefix < len(after) && before.text[prefix] == after[prefix] {
+		prefix++
+	}
+	for prefix > 0 && prefix < len(after) && !utf8.RuneStart(after[prefix]) {
+		prefix--
+	}
+	var suffix int
+	for suffix < len(before.text)-prefix && suffix < len(after)-prefix && before.text[len(before.text)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(after[len(after)-suffix]) {
+		suffix--
+	}
+
+	rest := after[:prefix] + after[len(after)-suffix:]
+	inserted := truncateText(after[prefix:len(after)-suffix], t.maxLength-textLength(rest, t.maxLengthUnit), t.maxLengthUnit)
+	text := after[:prefix] + inserted + after[len(after)-suffix:]
+	pos := prefix + len(inserted)
+	// The text might still exceed the maximum length when the text before the edit already exceeded it.
+	if textLength(text, t.maxLengthUnit) > t.maxLength {
+		text = truncateText(text, t.maxLength, t.maxLengthUnit)
+		pos = min(pos, len(text))
+	}
+	t.setTextAndSelection(text, pos, pos, -1)
+}
+
+// insertInputChars replaces the selection with the typed characters, and reports whether any character is inserted.
+// insertInputChars is used instead of the IME when the IME is not available, e.g. in tests.
+// Nothing is inserted when the text is not editable.
+func (t *Text) insertInputChars(input *guigui.InputState) bool {
+	if !t.editable {
+		return false
+	}
+	chars := input.AppendInputChars(nil)
+	if len(chars) == 0 {
+		return false
+	}
+	start, end := t.field.Selection()
+	str := string(chars)
+	text := t.field.Text()[:start] + str + t.field.Text()[end:]
+	t.setTextAndSelection(text, start+len(str), start+len(str), -1)
+	return true
+}
+
 func (t *Text) selectAll() {
 	t.setTextAndSelection(t.field.Text(), 0, len(t.field.Text()), -1)
 }
 
+func (t *Text) canCopy() bool {
+	start, end := t.field.Selection()
+	return start != end && !t.masked
+}
+
+// cut cuts the selected text to the clipboard. The masked text cannot be cut.
+func (t *Text) cut() error {
+	if !t.canCopy() {
+		return nil
+	}
+	start, end := t.field.Selection()
+	if err := clipboard.WriteAll(t.field.Text()[start:end]); err != nil {
+		return err
+	}
+	text := t.field.Text()[:start] + t.field.Text()[end:]
+	t.setTextAndSelection(text, start, start, -1)
+	return nil
+}
+
+// copy copies the selected text to the clipboard. The masked text cannot be copied.
+func (t *Text) copy() error {
+	if !t.canCopy() {
+		return nil
+	}
+	start, end := t.field.Selection()
+	return clipboard.WriteAll(t.field.Text()[start:end])
+}
+
+// paste replaces the selected text with the text in the clipboard.
+func (t *Text) paste() error {
+	start, end := t.field.Selection()
+	ct, err := clipboard.ReadAll()
+	if err != nil {
+		return err
+	}
+	text := t.field.Text()[:start] + ct + t.field.Text()[end:]
+	t.setTextAndSelection(text, start+len(ct), start+len(ct), -1)
+	return nil
+}
+
 func (t *Text) setTextAndSelection(text string, start, end int, shiftIndex int) {
 	t.selectionShiftIndex = shiftIndex
 	if start > end {
 		start, end = end, start
 	}
 
-	if s, e := t.field.Selection(); t.field.Text() == text && s == start && e == end {
+	s, e := t.field.Selection()
+	if t.field.Text() == text && s == start && e == end {
 		return
 	}
+	before := textEditState{
+		text:  t.field.Text(),
+		start: s,
+		end:   e,
+	}
 	t.field.SetTextAndSelection(text, start, end)
+	t.adjustSpans(before, textEditState{
+		text:  text,
+		start: start,
+		end:   end,
+	})
 	t.toAdjustScrollOffset = true
 	guigui.RequestRedraw(t)
 	t.resetCachedSize()
@@ -233,6 +443,24 @@ func (t *Text) SetEditable(editable bool) {
 	}
 	t.editable = editable
 	guigui.RequestRedraw(t)
+	// The cursor and the focusability depend on the editability.
+	guigui.RequestRelayout(t)
+}
+
+// SetMasked sets whether the text is masked, e.g. for a password.
+//
+// A masked text is rendered with a mask character for each grapheme cluster, and cannot be copied or cut.
+func (t *Text) SetMasked(masked bool) {
+	if t.masked == masked {
+		return
+	}
+	t.masked = masked
+	guigui.RequestRedraw(t)
+	t.resetCachedSize()
+}
+
+func (t *Text) IsMasked() bool {
+	return t.masked
 }
 
 func (t *Text) SetScrollable(context *guigui.Context, scrollable bool) {
@@ -257,17 +485,56 @@ func (t *Text) SetMultiline(multiline bool) {
 	t.resetCachedSize()
 }
 
+// SetAutoWrap sets whether the lines of a multiline text are wrapped automatically by the width.
+//
+// The lines are broken at the line break opportunities by the Unicode line breaking algorithm.
+// The lines are not wrapped unless the width is set by SetSize or SetWidth.
+func (t *Text) SetAutoWrap(autoWrap bool) {
+	if t.autoWrap == autoWrap {
+		return
+	}
+
+	t.autoWrap = autoWrap
+	guigui.RequestRedraw(t)
+	t.resetCachedSize()
+}
+
+func (t *Text) IsAutoWrap() bool {
+	return t.autoWrap
+}
+
+// SetTruncation sets how the text is truncated with an ellipsis when the text doesn't fit with the width.
+//
+// The text is not truncated unless the width is set by SetSize or SetWidth.
+// An editable text and an automatically wrapped text are not truncated.
+// While the cursor is on a truncated text, a tooltip shows the entire text.
+func (t *Text) SetTruncation(truncation TextTruncation) {
+	if t.truncation == truncation {
+		return
+	}
+
+	t.truncation = truncation
+	guigui.RequestRedraw(t)
+	t.resetCachedSize()
+}
+
+// IsTruncated reports whether the text is truncated.
+func (t *Text) IsTruncated(context *guigui.Context) bool {
+	tr := t.truncate(context)
+	return tr != nil && tr.isTruncated()
+}
+
 func (t *Text) textBounds(context *guigui.Context) image.Rectangle {
 	offsetX, offsetY := t.scrollOverlay.Offset()
 
 	b := guigui.Bounds(t)
 
-	tw, _ := text.Measure(t.textToDraw(), t.face(context), t.lineHeight(context))
+	tw := t.textWidth(context, t.textToDraw(context))
 	if b.Dx() < int(tw) {
 		b.Max.X = b.Min.X + int(tw)
 	}
 
-	th := t.textHeight(context, t.textToDraw())
+	th := t.textHeight(context, t.textToDraw(context))
 	switch t.vAlign {
 	case VerticalAlignTop:
 		b.Max.Y = b.Min.Y + th
@@ -284,11 +551,15 @@ func (t *Text) textBounds(context *guigui.Context) image.Rectangle {
 }
 
 func (t *Text) face(context *guigui.Context) text.Face {
-	size := FontSize(context) * (t.scaleMinus1 + 1)
 	weight := text.WeightMedium
 	if t.bold {
 		weight = text.WeightBold
 	}
+	return t.doFace(context, weight)
+}
+
+func (t *Text) doFace(context *guigui.Context, weight text.Weight) text.Face {
+	size := FontSize(context) * (t.scaleMinus1 + 1)
 	locales := append([]language.Tag(nil), t.locales...)
 	locales = context.AppendLocales(locales)
 	var liga bool
@@ -303,6 +574,13 @@ func (t *Text) lineHeight(context *guigui.Context) float64 {
 }
 
 func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if t.handleLinkInput(context) {
+		return guigui.HandleInputByWidget(t)
+	}
+	if t.handleContextMenuInput(context) {
+		return guigui.HandleInputByWidget(t)
+	}
+
 	if !t.selectable && !t.editable {
 		return guigui.HandleInputResult{}
 	}
@@ -310,31 +588,52 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 	textBounds := t.textBounds(context)
 
 	face := t.face(context)
-	cursorPosition := image.Pt(ebiten.CursorPosition())
+	input := context.Input()
+	cursorPosition := input.CursorPosition()
 	if t.dragging {
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-			idx := textIndexFromPosition(textBounds, cursorPosition, t.field.Text(), face, t.lineHeight(context), t.hAlign, t.vAlign)
+		if input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			idx := t.textIndexFromPosition(context, textBounds, cursorPosition, face, t.lineHeight(context))
 			if idx < t.selectionDragStart {
 				t.setTextAndSelection(t.field.Text(), idx, t.selectionDragStart, -1)
 			} else {
 				t.setTextAndSelection(t.field.Text(), t.selectionDragStart, idx, -1)
 			}
 		}
-		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		if input.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
 			t.dragging = false
 			t.selectionDragStart = -1
 		}
 		return guigui.HandleInputByWidget(t)
 	}
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		if cursorPosition.In(guigui.VisibleBounds(t)) {
-			t.dragging = true
-			idx := textIndexFromPosition(textBounds, cursorPosition, t.field.Text(), face, t.lineHeight(context), t.hAlign, t.vAlign)
-			t.selectionDragStart = idx
+			idx := t.textIndexFromPosition(context, textBounds, cursorPosition, face, t.lineHeight(context))
+			now := time.Now()
+			if now.Sub(t.lastClickTime) < 400*time.Millisecond && t.lastClickIndex == idx {
+				t.clickCount++
+			} else {
+				t.clickCount = 1
+			}
+			t.lastClickTime = now
+			t.lastClickIndex = idx
 			guigui.Focus(t)
-			if start, end := t.field.Selection(); start != idx || end != idx {
-				t.setTextAndSelection(t.field.Text(), idx, idx, -1)
+
+			switch t.clickCount {
+			case 1:
+				t.dragging = true
+				t.selectionDragStart = idx
+				if start, end := t.field.Selection(); start != idx || end != idx {
+					t.setTextAndSelection(t.field.Text(), idx, idx, -1)
+				}
+			case 2:
+				// Select the word by double-clicking.
+				start, end := t.wordRangeAt(idx)
+				t.setTextAndSelection(t.field.Text(), start, end, -1)
+			default:
+				// Select the line by triple-clicking.
+				start, end := lineRangeAt(t.field.Text(), idx)
+				t.setTextAndSelection(t.field.Text(), start, end, -1)
 			}
 			return guigui.HandleInputByWidget(t)
 		}
@@ -355,8 +654,11 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 	}
 
 	start, _ := t.field.Selection()
+	before := t.editState()
 	var processed bool
-	if x, _, bottom, ok := textPosition(textBounds, t.field.Text(), start, face, t.lineHeight(context), t.hAlign, t.vAlign); ok {
+	if !input.IsIMEAvailable() {
+		processed = t.insertInputChars(input)
+	} else if x, _, bottom, ok := t.textPosition(context, textBounds, start, face, t.lineHeight(context)); ok {
 		var err error
 		processed, err = t.field.HandleInput(int(x), int(bottom))
 		if err != nil {
@@ -365,6 +667,12 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 		}
 	}
 	if processed {
+		// The characters committed by the IME don't go through setTextAndSelection.
+		t.adjustSpans(before, t.editState())
+		t.enforceMaxLength(before)
+		if t.field.Text() != before.text {
+			t.history.record(before, t.editState(), textEditKindTyping)
+		}
 		guigui.RequestRedraw(t)
 		t.adjustScrollOffset(context)
 		return guigui.HandleInputByWidget(t)
@@ -375,13 +683,30 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 		return guigui.HandleInputResult{}
 	}
 
+	if t.keyHandler != nil && t.keyHandler(context) {
+		return guigui.HandleInputByWidget(t)
+	}
+
 	// TODO: Use WebAPI to detect OS is runtime.GOOS == "js"
 	isWindows := runtime.GOOS == "windows"
 	isDarwin := runtime.GOOS == "darwin"
 
+	// keyHandled reports whether the key inputs are handled by the text, so that they are not dispatched to commands.
+	keyHandled := true
+	var editKeyHandled bool
+	editKind := textEditKindOther
+	var restored bool
 	if t.editable {
+		editKeyHandled = true
 		switch {
-		case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		case input.IsModifierPressed(guigui.ModifierPrimary) && !input.IsKeyPressed(ebiten.KeyShift) && isShortcutKeyRepeating(input, ebiten.KeyZ):
+			t.Undo()
+			restored = true
+		case input.IsModifierPressed(guigui.ModifierPrimary|guigui.ModifierShift) && isShortcutKeyRepeating(input, ebiten.KeyZ) ||
+			isWindows && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyY):
+			t.Redo()
+			restored = true
+		case input.IsKeyJustPressed(ebiten.KeyEnter):
 			if t.multiline {
 				start, end := t.field.Selection()
 				text := t.field.Text()[:start] + "\n" + t.field.Text()[end:]
@@ -392,8 +717,9 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 			if t.onEnterPressed != nil {
 				t.onEnterPressed(t.field.Text())
 			}
-		case isKeyRepeating(ebiten.KeyBackspace) ||
-			isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyH):
+		case isKeyRepeating(input, ebiten.KeyBackspace) ||
+			isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyH):
+			editKind = textEditKindDeleting
 			start, end := t.field.Selection()
 			if start != end {
 				text := t.field.Text()[:start] + t.field.Text()[end:]
@@ -402,9 +728,10 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				text, pos := backspaceOnClusters(t.field.Text(), face, start)
 				t.setTextAndSelection(text, pos, pos, -1)
 			}
-		case isWindows && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyD) ||
-			isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyD):
+		case isWindows && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyD) ||
+			isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyD):
 			// Delete
+			editKind = textEditKindDeleting
 			start, end := t.field.Selection()
 			if start != end {
 				text := t.field.Text()[:start] + t.field.Text()[end:]
@@ -414,37 +741,70 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				t.setTextAndSelection(text, pos, pos, -1)
 			}
 
-		case isWindows && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyX) ||
-			isDarwin && ebiten.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(ebiten.KeyX):
-			// Cut
-			start, end := t.field.Selection()
-			if start != end {
-				if err := clipboard.WriteAll(t.field.Text()[start:end]); err != nil {
-					slog.Error(err.Error())
-					return guigui.HandleInputResult{}
-				}
-				text := t.field.Text()[:start] + t.field.Text()[end:]
-				t.setTextAndSelection(text, start, start, -1)
+		case input.IsModifierPressed(guigui.ModifierPrimary) && isShortcutKeyRepeating(input, ebiten.KeyX):
+			if err := t.cut(); err != nil {
+				slog.Error(err.Error())
+				return guigui.HandleInputResult{}
 			}
-		case isWindows && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyV) ||
-			isDarwin && ebiten.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(ebiten.KeyV):
-			// Paste
-			start, end := t.field.Selection()
-			ct, err := clipboard.ReadAll()
-			if err != nil {
+		case input.IsModifierPressed(guigui.ModifierPrimary) && isShortcutKeyRepeating(input, ebiten.KeyV):
+			if err := t.paste(); err != nil {
 				slog.Error(err.Error())
 				return guigui.HandleInputResult{}
 			}
-			text := t.field.Text()[:start] + ct + t.field.Text()[end:]
-			t.setTextAndSelection(text, start+len(ct), start+len(ct), -1)
+		default:
+			editKeyHandled = false
 		}
 	}
 
 	switch {
-	case isKeyRepeating(ebiten.KeyLeft) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyB):
+	case !isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(input, ebiten.KeyLeft) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyAlt) && isKeyRepeating(input, ebiten.KeyLeft):
+		// Move to the previous word.
+		from := t.selectionMovingIndex(false, input.IsKeyPressed(ebiten.KeyShift))
+		t.moveSelection(t.prevWordPosition(from), false, input.IsKeyPressed(ebiten.KeyShift))
+	case !isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(input, ebiten.KeyRight) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyAlt) && isKeyRepeating(input, ebiten.KeyRight):
+		// Move to the next word.
+		from := t.selectionMovingIndex(true, input.IsKeyPressed(ebiten.KeyShift))
+		t.moveSelection(t.nextWordPosition(from), true, input.IsKeyPressed(ebiten.KeyShift))
+	case !isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(input, ebiten.KeyHome) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(input, ebiten.KeyUp):
+		// Move to the start of the text.
+		t.moveSelection(0, false, input.IsKeyPressed(ebiten.KeyShift))
+	case !isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(input, ebiten.KeyEnd) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(input, ebiten.KeyDown):
+		// Move to the end of the text.
+		t.moveSelection(len(t.field.Text()), true, input.IsKeyPressed(ebiten.KeyShift))
+	case isKeyRepeating(input, ebiten.KeyHome) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(input, ebiten.KeyLeft):
+		// Move to the start of the line.
+		from := t.selectionMovingIndex(false, input.IsKeyPressed(ebiten.KeyShift))
+		start, _ := t.visualLineRangeAt(context, from)
+		t.moveSelection(start, false, input.IsKeyPressed(ebiten.KeyShift))
+	case isKeyRepeating(input, ebiten.KeyEnd) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(input, ebiten.KeyRight):
+		// Move to the end of the line.
+		from := t.selectionMovingIndex(true, input.IsKeyPressed(ebiten.KeyShift))
+		_, end := t.visualLineRangeAt(context, from)
+		t.moveSelection(end, true, input.IsKeyPressed(ebiten.KeyShift))
+	case isKeyRepeating(input, ebiten.KeyPageUp) || isKeyRepeating(input, ebiten.KeyPageDown):
+		forward := isKeyRepeating(input, ebiten.KeyPageDown)
+		lh := t.lineHeight(context)
+		from := t.selectionMovingIndex(forward, input.IsKeyPressed(ebiten.KeyShift))
+		if x, y0, y1, ok := t.textPosition(context, textBounds, from, face, lh); ok {
+			// Move by the visible lines.
+			d := float64(max(1, int(float64(guigui.VisibleBounds(t).Dy())/lh))) * lh
+			if !forward {
+				d = -d
+			}
+			y := (y0+y1)/2 + d
+			idx := t.textIndexFromPosition(context, textBounds, image.Pt(int(x), int(y)), face, lh)
+			t.moveSelection(idx, forward, input.IsKeyPressed(ebiten.KeyShift))
+		}
+	case isKeyRepeating(input, ebiten.KeyLeft) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyB):
 		start, end := t.field.Selection()
-		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		if input.IsKeyPressed(ebiten.KeyShift) {
 			if t.selectionShiftIndex == end {
 				pos := prevPositionOnClusters(t.field.Text(), face, end)
 				t.setTextAndSelection(t.field.Text(), start, pos, pos)
@@ -460,10 +820,10 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				t.setTextAndSelection(t.field.Text(), pos, pos, -1)
 			}
 		}
-	case isKeyRepeating(ebiten.KeyRight) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyF):
+	case isKeyRepeating(input, ebiten.KeyRight) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyF):
 		start, end := t.field.Selection()
-		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		if input.IsKeyPressed(ebiten.KeyShift) {
 			if t.selectionShiftIndex == start {
 				pos := nextPositionOnClusters(t.field.Text(), face, start)
 				t.setTextAndSelection(t.field.Text(), pos, end, pos)
@@ -479,10 +839,10 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				t.setTextAndSelection(t.field.Text(), pos, pos, -1)
 			}
 		}
-	case isKeyRepeating(ebiten.KeyUp) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyP):
+	case isKeyRepeating(input, ebiten.KeyUp) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyP):
 		lh := t.lineHeight(context)
-		shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+		shift := input.IsKeyPressed(ebiten.KeyShift)
 		var moveEnd bool
 		start, end := t.field.Selection()
 		idx := start
@@ -490,9 +850,9 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 			idx = end
 			moveEnd = true
 		}
-		if x, y0, y1, ok := textPosition(textBounds, t.field.Text(), idx, face, lh, t.hAlign, t.vAlign); ok {
+		if x, y0, y1, ok := t.textPosition(context, textBounds, idx, face, lh); ok {
 			y := (y0+y1)/2 - lh
-			idx := textIndexFromPosition(textBounds, image.Pt(int(x), int(y)), t.field.Text(), face, lh, t.hAlign, t.vAlign)
+			idx := t.textIndexFromPosition(context, textBounds, image.Pt(int(x), int(y)), face, lh)
 			if shift {
 				if moveEnd {
 					t.setTextAndSelection(t.field.Text(), start, idx, idx)
@@ -503,10 +863,10 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				t.setTextAndSelection(t.field.Text(), idx, idx, -1)
 			}
 		}
-	case isKeyRepeating(ebiten.KeyDown) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyN):
+	case isKeyRepeating(input, ebiten.KeyDown) ||
+		isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyN):
 		lh := t.lineHeight(context)
-		shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+		shift := input.IsKeyPressed(ebiten.KeyShift)
 		var moveStart bool
 		start, end := t.field.Selection()
 		idx := end
@@ -514,9 +874,9 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 			idx = start
 			moveStart = true
 		}
-		if x, y0, y1, ok := textPosition(textBounds, t.field.Text(), idx, face, lh, t.hAlign, t.vAlign); ok {
+		if x, y0, y1, ok := t.textPosition(context, textBounds, idx, face, lh); ok {
 			y := (y0+y1)/2 + lh
-			idx := textIndexFromPosition(textBounds, image.Pt(int(x), int(y)), t.field.Text(), face, lh, t.hAlign, t.vAlign)
+			idx := t.textIndexFromPosition(context, textBounds, image.Pt(int(x), int(y)), face, lh)
 			if shift {
 				if moveStart {
 					t.setTextAndSelection(t.field.Text(), idx, end, idx)
@@ -527,42 +887,36 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 				t.setTextAndSelection(t.field.Text(), idx, idx, -1)
 			}
 		}
-	case isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyA):
+	case isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyA):
 		idx := 0
 		start, end := t.field.Selection()
 		if i := strings.LastIndex(t.field.Text()[:start], "\n"); i >= 0 {
 			idx = i + 1
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		if input.IsKeyPressed(ebiten.KeyShift) {
 			t.setTextAndSelection(t.field.Text(), idx, end, idx)
 		} else {
 			t.setTextAndSelection(t.field.Text(), idx, idx, -1)
 		}
-	case isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyE):
+	case isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyE):
 		idx := len(t.field.Text())
 		start, end := t.field.Selection()
 		if i := strings.Index(t.field.Text()[end:], "\n"); i >= 0 {
 			idx = end + i
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		if input.IsKeyPressed(ebiten.KeyShift) {
 			t.setTextAndSelection(t.field.Text(), start, idx, idx)
 		} else {
 			t.setTextAndSelection(t.field.Text(), idx, idx, -1)
 		}
-	case isWindows && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyA) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(ebiten.KeyA):
+	case input.IsModifierPressed(guigui.ModifierPrimary) && isShortcutKeyRepeating(input, ebiten.KeyA):
 		t.selectAll()
-	case isWindows && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyC) ||
-		isDarwin && ebiten.IsKeyPressed(ebiten.KeyMeta) && isKeyRepeating(ebiten.KeyC):
-		// Copy
-		start, end := t.field.Selection()
-		if start != end {
-			if err := clipboard.WriteAll(t.field.Text()[start:end]); err != nil {
-				slog.Error(err.Error())
-				return guigui.HandleInputResult{}
-			}
+	case input.IsModifierPressed(guigui.ModifierPrimary) && isShortcutKeyRepeating(input, ebiten.KeyC):
+		if err := t.copy(); err != nil {
+			slog.Error(err.Error())
+			return guigui.HandleInputResult{}
 		}
-	case isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyK):
+	case isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyK):
 		// 'Kill' the text after the cursor or the selection.
 		start, end := t.field.Selection()
 		if start == end {
@@ -576,31 +930,41 @@ func (t *Text) HandleInput(context *guigui.Context) guigui.HandleInputResult {
 		t.temporaryClipboard = t.field.Text()[start:end]
 		text := t.field.Text()[:start] + t.field.Text()[end:]
 		t.setTextAndSelection(text, start, start, -1)
-	case isDarwin && ebiten.IsKeyPressed(ebiten.KeyControl) && isKeyRepeating(ebiten.KeyY):
+	case isDarwin && input.IsKeyPressed(ebiten.KeyControl) && isShortcutKeyRepeating(input, ebiten.KeyY):
 		// 'Yank' the killed text.
 		if t.temporaryClipboard != "" {
 			start, _ := t.field.Selection()
 			text := t.field.Text()[:start] + t.temporaryClipboard + t.field.Text()[start:]
 			t.setTextAndSelection(text, start+len(t.temporaryClipboard), start+len(t.temporaryClipboard), -1)
 		}
+	default:
+		keyHandled = slices.ContainsFunc(textKeys, input.IsKeyJustPressed)
+	}
+
+	if !restored && t.field.Text() != before.text {
+		t.enforceMaxLength(before)
+		t.history.record(before, t.editState(), editKind)
 	}
 
+	if keyHandled || editKeyHandled {
+		return guigui.HandleInputByWidget(t)
+	}
 	return guigui.HandleInputResult{}
 }
 
 func (t *Text) adjustScrollOffset(context *guigui.Context) {
 	t.updateContentSize(context)
 
-	start, end, ok := t.selectionToDraw()
+	start, end, ok := t.selectionToDraw(context)
 	if !ok {
 		return
 	}
-	text := t.textToDraw()
+	text := t.textToDraw(context)
 
 	tb := t.textBounds(context)
 	face := t.face(context)
 	bounds := guigui.Bounds(t)
-	if x, _, y, ok := textPosition(tb, text, end, face, t.lineHeight(context), t.hAlign, t.vAlign); ok {
+	if x, _, y, ok := t.drawnTextPosition(context, tb, text, end, face, t.lineHeight(context)); ok {
 		var dx, dy float64
 		if max := float64(bounds.Max.X); x > max {
 			dx = max - x
@@ -610,7 +974,7 @@ func (t *Text) adjustScrollOffset(context *guigui.Context) {
 		}
 		t.scrollOverlay.SetOffsetByDelta(dx, dy)
 	}
-	if x, y, _, ok := textPosition(tb, text, start, face, t.lineHeight(context), t.hAlign, t.vAlign); ok {
+	if x, y, _, ok := t.drawnTextPosition(context, tb, text, start, face, t.lineHeight(context)); ok {
 		var dx, dy float64
 		if min := float64(bounds.Min.X); x < min {
 			dx = min - x
@@ -622,11 +986,113 @@ func (t *Text) adjustScrollOffset(context *guigui.Context) {
 	}
 }
 
-func (t *Text) textToDraw() string {
+// textToDraw returns the text to draw, which is masked, truncated and wrapped if needed.
+func (t *Text) textToDraw(context *guigui.Context) string {
+	if tr := t.truncate(context); tr != nil {
+		return tr.truncated
+	}
+	if w := t.wrap(context); w != nil {
+		return w.wrapped
+	}
+	return t.unwrappedTextToDraw()
+}
+
+func (t *Text) unwrappedTextToDraw() string {
+	if t.masked {
+		return t.maskedTextCache.get(t.field.TextForRendering()).masked
+	}
 	return t.field.TextForRendering()
 }
 
-func (t *Text) selectionToDraw() (start, end int, ok bool) {
+// wrap returns the wrap of the text to draw, or nil if the text is not wrapped.
+func (t *Text) wrap(context *guigui.Context) *textWrap {
+	if !t.autoWrap || !t.multiline || !t.widthSet {
+		return nil
+	}
+	str := t.unwrappedTextToDraw()
+	face := t.face(context)
+	key := textWrapKey{
+		text:   str,
+		width:  float64(t.width),
+		face:   face,
+		styled: t.isStyled(),
+	}
+	return t.wrapCache.get(key, t.advanceFunc(context, str))
+}
+
+// advanceFunc returns the function to return the width of the range of str.
+func (t *Text) advanceFunc(context *guigui.Context, str string) func(start, end int) float64 {
+	face := t.face(context)
+	if t.isStyled() {
+		return func(start, end int) float64 {
+			return t.styledAdvance(context, str, start, end)
+		}
+	}
+	return func(start, end int) float64 {
+		return text.Advance(str[start:end], face)
+	}
+}
+
+// truncate returns the truncation of the text to draw, or nil if the text is not truncated.
+func (t *Text) truncate(context *guigui.Context) *textTruncationCache {
+	if t.truncation == TextTruncationNone || !t.widthSet || t.editable || t.autoWrap && t.multiline {
+		return nil
+	}
+	str := t.unwrappedTextToDraw()
+	face := t.face(context)
+	styled := t.isStyled()
+	key := textTruncationKey{
+		text:       str,
+		width:      float64(t.width),
+		face:       face,
+		styled:     styled,
+		truncation: t.truncation,
+	}
+	return t.truncationCache.get(key, func(start, end int) float64 {
+		if styled {
+			return t.styledAdvance(context, str, start, end)
+		}
+		return text.Advance(str[start:end], face)
+	})
+}
+
+// drawIndex converts an index in the text to an index in the text to draw.
+func (t *Text) drawIndex(context *guigui.Context, index int) int {
+	if t.masked {
+		index = t.maskedTextCache.get(t.field.TextForRendering()).maskedIndex(index)
+	}
+	if tr := t.truncate(context); tr != nil {
+		index = tr.truncatedIndex(index)
+	}
+	if w := t.wrap(context); w != nil {
+		index = w.wrappedIndex(index)
+	}
+	return index
+}
+
+// textIndex converts an index in the text to draw to an index in the text.
+func (t *Text) textIndex(context *guigui.Context, drawIndex int) int {
+	if w := t.wrap(context); w != nil {
+		drawIndex = w.index(drawIndex)
+	}
+	if tr := t.truncate(context); tr != nil {
+		drawIndex = tr.index(drawIndex)
+	}
+	if t.masked {
+		drawIndex = t.maskedTextCache.get(t.field.TextForRendering()).index(drawIndex)
+	}
+	return drawIndex
+}
+
+func (t *Text) selectionToDraw(context *guigui.Context) (start, end int, ok bool) {
+	start, end, ok = t.selectionToDrawInText()
+	if !ok {
+		return 0, 0, false
+	}
+	return t.drawIndex(context, start), t.drawIndex(context, end), true
+}
+
+func (t *Text) selectionToDrawInText() (start, end int, ok bool) {
 	s, e := t.field.Selection()
 	if !t.editable {
 		return s, e, true
@@ -647,7 +1113,15 @@ func (t *Text) selectionToDraw() (start, end int, ok bool) {
 	return 0, 0, false
 }
 
-func (t *Text) compositionSelectionToDraw() (uStart, cStart, cEnd, uEnd int, ok bool) {
+func (t *Text) compositionSelectionToDraw(context *guigui.Context) (uStart, cStart, cEnd, uEnd int, ok bool) {
+	uStart, cStart, cEnd, uEnd, ok = t.compositionSelectionToDrawInText()
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	return t.drawIndex(context, uStart), t.drawIndex(context, cStart), t.drawIndex(context, cEnd), t.drawIndex(context, uEnd), true
+}
+
+func (t *Text) compositionSelectionToDrawInText() (uStart, cStart, cEnd, uEnd int, ok bool) {
 	if !t.editable {
 		return 0, 0, 0, 0, false
 	}
@@ -672,32 +1146,211 @@ func (t *Text) compositionSelectionToDraw() (uStart, cStart, cEnd, uEnd int, ok
 func (t *Text) Update(context *guigui.Context) error {
 	guigui.Hide(&t.scrollOverlay)
 
-	if !t.prevFocused && guigui.IsFocused(t) {
+	if t.toAdjustScrollOffset && !guigui.VisibleBounds(t).Empty() {
+		t.adjustScrollOffset(context)
+		t.toAdjustScrollOffset = false
+	}
+
+	return nil
+}
+
+func (t *Text) OnFocusChanged(context *guigui.Context, event guigui.FocusChangedEvent) {
+	if event.Focused {
 		t.field.Focus()
 		t.cursor.resetCounter()
 		start, end := t.field.Selection()
 		if start < 0 || end < 0 {
 			t.selectAll()
 		}
-	} else if t.prevFocused && !guigui.IsFocused(t) {
-		t.applyFilter()
+		return
 	}
+	t.applyFilter()
+}
 
-	if t.toAdjustScrollOffset && !guigui.VisibleBounds(t).Empty() {
-		t.adjustScrollOffset(context)
-		t.toAdjustScrollOffset = false
+func (t *Text) Accessibility(context *guigui.Context) guigui.Accessibility {
+	a := guigui.Accessibility{
+		Multiline: t.multiline,
+		Leaf:      true,
 	}
+	if t.editable {
+		a.Role = guigui.AccessibilityRoleTextField
+		a.Value = t.unwrappedTextToDraw()
+	} else {
+		a.Role = guigui.AccessibilityRoleText
+		a.Label = t.unwrappedTextToDraw()
+		a.ReadOnly = true
+	}
+	if t.editable || t.selectable {
+		a.Actions = []guigui.AccessibilityAction{guigui.AccessibilityActionFocus}
+	}
+	return a
+}
 
-	t.prevFocused = guigui.IsFocused(t)
+func (t *Text) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	// Focusing is done by guigui.
+	return false
+}
 
-	return nil
+// textIndexFromPosition is like the function textIndexFromPosition, but considers the mask, the wrap and the styles.
+func (t *Text) textIndexFromPosition(context *guigui.Context, textBounds image.Rectangle, position image.Point, face text.Face, lineHeight float64) int {
+	idx := t.drawnTextIndexFromPosition(context, textBounds, position, t.textToDraw(context), face, lineHeight)
+	return t.textIndex(context, idx)
+}
+
+// textPosition is like the function textPosition, but considers the mask, the wrap and the styles.
+func (t *Text) textPosition(context *guigui.Context, textBounds image.Rectangle, index int, face text.Face, lineHeight float64) (x, top, bottom float64, ok bool) {
+	return t.drawnTextPosition(context, textBounds, t.textToDraw(context), t.drawIndex(context, index), face, lineHeight)
+}
+
+// visualLineRangeAt returns the range of the visual line at the position.
+// The range doesn't include the trailing spaces at a soft line break.
+func (t *Text) visualLineRangeAt(context *guigui.Context, position int) (int, int) {
+	w := t.wrap(context)
+	if w == nil {
+		return lineRangeAt(t.field.Text(), position)
+	}
+	start, end := lineRangeAt(t.textToDraw(context), t.drawIndex(context, position))
+	start, end = t.textIndex(context, start), t.textIndex(context, end)
+	if t.wrapCache.isSoftLineBreak(end) {
+		end = trimTrailingSpaces(t.field.Text(), start, end)
+	}
+	return start, end
+}
+
+// drawnTextIndexFromPosition is like textIndexFromPosition, but str is the text to draw.
+func (t *Text) drawnTextIndexFromPosition(context *guigui.Context, textBounds image.Rectangle, position image.Point, str string, face text.Face, lineHeight float64) int {
+	if t.isStyled() {
+		return t.styledTextIndexFromPosition(context, textBounds, position, str, face, lineHeight)
+	}
+	return textIndexFromPosition(textBounds, position, str, face, lineHeight, t.hAlign, t.vAlign)
+}
+
+// drawnTextPosition is like textPosition, but str and index are in the text to draw.
+func (t *Text) drawnTextPosition(context *guigui.Context, textBounds image.Rectangle, str string, index int, face text.Face, lineHeight float64) (x, top, bottom float64, ok bool) {
+	if t.isStyled() {
+		return t.styledTextPosition(context, textBounds, str, index, face, lineHeight)
+	}
+	return textPosition(textBounds, str, index, face, lineHeight, t.hAlign, t.vAlign)
+}
+
+// A masked text is treated as one word so that the word boundaries don't leak the text.
+
+func (t *Text) prevWordPosition(position int) int {
+	if t.masked {
+		start, _ := lineRangeAt(t.field.Text(), position)
+		return start
+	}
+	return prevWordPosition(t.field.Text(), position)
+}
+
+func (t *Text) nextWordPosition(position int) int {
+	if t.masked {
+		_, end := lineRangeAt(t.field.Text(), position)
+		return end
+	}
+	return nextWordPosition(t.field.Text(), position)
+}
+
+func (t *Text) wordRangeAt(position int) (int, int) {
+	if t.masked {
+		return lineRangeAt(t.field.Text(), position)
+	}
+	return wordRangeAt(t.field.Text(), position)
+}
+
+// selectionMovingIndex returns the index where the cursor moves from.
+func (t *Text) selectionMovingIndex(forward bool, shift bool) int {
+	start, end := t.field.Selection()
+	if shift && (t.selectionShiftIndex == start || t.selectionShiftIndex == end) {
+		return t.selectionShiftIndex
+	}
+	if forward {
+		return end
+	}
+	return start
+}
+
+// moveSelection moves the cursor to the index.
+// If shift is true, the selection is extended to the index instead.
+func (t *Text) moveSelection(index int, forward bool, shift bool) {
+	if !shift {
+		t.setTextAndSelection(t.field.Text(), index, index, -1)
+		return
+	}
+	start, end := t.field.Selection()
+	anchor := start
+	if t.selectionShiftIndex == start || t.selectionShiftIndex != end && !forward {
+		anchor = end
+	}
+	t.setTextAndSelection(t.field.Text(), anchor, index, index)
+}
+
+func (t *Text) editState() textEditState {
+	start, end := t.field.Selection()
+	return textEditState{
+		text:  t.field.Text(),
+		start: start,
+		end:   end,
+		spans: slices.Clone(t.spans),
+	}
+}
+
+// CanUndo reports whether the last edit can be undone.
+func (t *Text) CanUndo() bool {
+	return t.editable && t.history.canUndo()
+}
+
+// CanRedo reports whether the last undone edit can be redone.
+func (t *Text) CanRedo() bool {
+	return t.editable && t.history.canRedo()
+}
+
+// Undo restores the text and the selection before the last edit.
+func (t *Text) Undo() {
+	if !t.editable {
+		return
+	}
+	state, ok := t.history.undo(t.editState())
+	if !ok {
+		return
+	}
+	t.restoreEditState(state)
+}
+
+// Redo restores the text and the selection undone by the last Undo.
+func (t *Text) Redo() {
+	if !t.editable {
+		return
+	}
+	state, ok := t.history.redo(t.editState())
+	if !ok {
+		return
+	}
+	t.restoreEditState(state)
+}
+
+func (t *Text) restoreEditState(state textEditState) {
+	t.setTextAndSelection(state.text, state.start, state.end, -1)
+	// The spans are restored as they were, as deleted styles cannot be recovered from the edit.
+	if state.spans != nil {
+		t.spans = slices.Clone(state.spans)
+		t.styledText = state.text
+		t.styledLinesCache = nil
+		t.wrapCache = textWrap{}
+		t.resetCachedSize()
+	}
+	// While the text is being edited, the filter is applied when the editing is committed.
+	if !guigui.IsFocused(t) {
+		t.applyFilter()
+	}
 }
 
 func (t *Text) applyFilter() {
 	if t.filter != nil {
-		start, end := t.field.Selection()
-		text, start, end := t.filter(t.field.Text(), start, end)
+		before := t.editState()
+		text, start, end := t.filter(before.text, before.start, before.end)
 		t.setTextAndSelection(text, start, end, -1)
+		t.enforceMaxLength(before)
 	}
 }
 
@@ -712,10 +1365,15 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 		return
 	}
 
-	text := t.textToDraw()
+	text := t.textToDraw(context)
 	face := t.face(context)
+	styled := t.isStyled()
+
+	if styled {
+		t.drawStyledText(context, dst, textBounds, text, face, true)
+	}
 
-	if start, end, ok := t.selectionToDraw(); ok {
+	if start, end, ok := t.selectionToDraw(context); ok {
 		var tailIndices []int
 		for i, r := range text[start:end] {
 			if r != '\n' {
@@ -727,8 +1385,8 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 
 		headIdx := start
 		for _, idx := range tailIndices {
-			x0, top0, bottom0, ok0 := textPosition(textBounds, text, headIdx, face, t.lineHeight(context), t.hAlign, t.vAlign)
-			x1, _, _, ok1 := textPosition(textBounds, text, idx, face, t.lineHeight(context), t.hAlign, t.vAlign)
+			x0, top0, bottom0, ok0 := t.drawnTextPosition(context, textBounds, text, headIdx, face, t.lineHeight(context))
+			x1, _, _, ok1 := t.drawnTextPosition(context, textBounds, text, idx, face, t.lineHeight(context))
 			if ok0 && ok1 {
 				x := float32(x0)
 				y := float32(top0)
@@ -740,14 +1398,14 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 		}
 	}
 
-	if uStart, cStart, cEnd, uEnd, ok := t.compositionSelectionToDraw(); ok {
+	if uStart, cStart, cEnd, uEnd, ok := t.compositionSelectionToDraw(context); ok {
 		// Assume that the composition is always in the same line.
 		if strings.Contains(text[uStart:uEnd], "\n") {
 			slog.Error("composition text must not contain '\\n'")
 		}
 		{
-			x0, _, bottom0, ok0 := textPosition(textBounds, text, uStart, face, t.lineHeight(context), t.hAlign, t.vAlign)
-			x1, _, _, ok1 := textPosition(textBounds, text, uEnd, face, t.lineHeight(context), t.hAlign, t.vAlign)
+			x0, _, bottom0, ok0 := t.drawnTextPosition(context, textBounds, text, uStart, face, t.lineHeight(context))
+			x1, _, _, ok1 := t.drawnTextPosition(context, textBounds, text, uEnd, face, t.lineHeight(context))
 			if ok0 && ok1 {
 				x := float32(x0)
 				y := float32(bottom0) - float32(cursorWidth(context))
@@ -757,8 +1415,8 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 			}
 		}
 		{
-			x0, _, bottom0, ok0 := textPosition(textBounds, text, cStart, face, t.lineHeight(context), t.hAlign, t.vAlign)
-			x1, _, _, ok1 := textPosition(textBounds, text, cEnd, face, t.lineHeight(context), t.hAlign, t.vAlign)
+			x0, _, bottom0, ok0 := t.drawnTextPosition(context, textBounds, text, cStart, face, t.lineHeight(context))
+			x1, _, _, ok1 := t.drawnTextPosition(context, textBounds, text, cEnd, face, t.lineHeight(context))
 			if ok0 && ok1 {
 				x := float32(x0)
 				y := float32(bottom0) - float32(cursorWidth(context))
@@ -769,12 +1427,22 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 		}
 	}
 
-	var clr color.RGBA
+	if styled {
+		t.drawStyledText(context, dst, textBounds, text, face, false)
+		return
+	}
+	drawText(textBounds, dst, text, face, t.lineHeight(context), t.hAlign, t.vAlign, t.textColor(context))
+}
+
+func (t *Text) textColor(context *guigui.Context) color.RGBA {
 	if t.color != nil {
-		clr = color.RGBAModel.Convert(t.color).(color.RGBA)
-	} else {
-		clr = color.RGBAModel.Convert(DefaultTextColor(context)).(color.RGBA)
+		return t.applyOpacity(t.color)
 	}
+	return t.applyOpacity(DefaultTextColor(context))
+}
+
+func (t *Text) applyOpacity(c color.Color) color.RGBA {
+	clr := color.RGBAModel.Convert(c).(color.RGBA)
 	if t.transparent > 0 {
 		opacity := 1 - t.transparent
 		clr = color.RGBA{
@@ -784,7 +1452,7 @@ func (t *Text) Draw(context *guigui.Context, dst *ebiten.Image) {
 			A: byte(float64(clr.A) * opacity),
 		}
 	}
-	drawText(textBounds, dst, text, face, t.lineHeight(context), t.hAlign, t.vAlign, clr)
+	return clr
 }
 
 func (t *Text) Size(context *guigui.Context) (int, int) {
@@ -807,11 +1475,84 @@ func (t *Text) Size(context *guigui.Context) (int, int) {
 	return w, h
 }
 
+// Measure implements guigui.Measurer.
+//
+// The sizes are by the content, and don't depend on the size set by SetSize.
+// The preferred height of an automatically wrapped text is for the lines wrapped by the maximum width of the constraints.
+func (t *Text) Measure(context *guigui.Context, constraints guigui.Constraints) guigui.Measurement {
+	str := t.unwrappedTextToDraw()
+	w := t.unwrappedTextWidth(context)
+	lines := strings.Count(str, "\n") + 1
+	minW := w
+	if t.autoWrap && t.multiline {
+		if maxW := float64(constraints.Max.X); constraints.HasBoundedWidth() && w > maxW {
+			lines += len(appendSoftLineBreaks(nil, str, maxW, t.advanceFunc(context, str)))
+			w = maxW
+		}
+		minW = 0
+	} else {
+		// Match the width with TextSize.
+		w *= t.scaleMinus1 + 1
+		minW = w
+		if t.truncation != TextTruncationNone && !t.editable {
+			minW = 0
+		}
+	}
+	h := int(t.lineHeight(context) * float64(lines))
+	return guigui.Measurement{
+		Preferred: image.Pt(int(math.Ceil(w)), h),
+		Min:       image.Pt(int(math.Ceil(minW)), h),
+		Max:       image.Pt(guigui.Unbounded, guigui.Unbounded),
+	}
+}
+
 func (t *Text) TextSize(context *guigui.Context) (int, int) {
-	w, _ := text.Measure(t.textToDraw(), t.face(context), t.lineHeight(context))
+	w := t.naturalTextWidth(context)
 	w *= t.scaleMinus1 + 1
-	h := t.textHeight(context, t.textToDraw())
-	return int(w), h
+	h := t.textHeight(context, t.textToDraw(context))
+	// Round up the width so that the text fits with the size without being truncated.
+	return int(math.Ceil(w)), h
+}
+
+func (t *Text) textWidth(context *guigui.Context, str string) float64 {
+	if t.isStyled() {
+		return t.styledTextWidth(context, str)
+	}
+	w, _ := text.Measure(str, t.face(context), t.lineHeight(context))
+	return w
+}
+
+// naturalTextWidth returns the width of the text without the truncation.
+func (t *Text) naturalTextWidth(context *guigui.Context) float64 {
+	if t.truncate(context) == nil {
+		return t.textWidth(context, t.textToDraw(context))
+	}
+	return t.unwrappedTextWidth(context)
+}
+
+// unwrappedTextWidth returns the width of the text without the truncation and the wrapping.
+func (t *Text) unwrappedTextWidth(context *guigui.Context) float64 {
+	str := t.unwrappedTextToDraw()
+	face := t.face(context)
+	styled := t.isStyled()
+	var w float64
+	var lineStart int
+	for {
+		lineEnd := len(str)
+		if i := strings.IndexByte(str[lineStart:], '\n'); i >= 0 {
+			lineEnd = lineStart + i
+		}
+		if styled {
+			w = max(w, t.styledAdvance(context, str, lineStart, lineEnd))
+		} else {
+			w = max(w, text.Advance(str[lineStart:lineEnd], face))
+		}
+		if lineEnd == len(str) {
+			break
+		}
+		lineStart = lineEnd + 1
+	}
+	return w
 }
 
 func (t *Text) textHeight(context *guigui.Context, str string) int {
@@ -820,28 +1561,49 @@ func (t *Text) textHeight(context *guigui.Context, str string) int {
 }
 
 func (t *Text) SetSize(width, height int) {
+	if t.widthSet && t.heightSet && t.width == width && t.height == height {
+		return
+	}
 	t.widthSet = true
 	t.heightSet = true
 	t.width = width
 	t.height = height
+	t.resetCachedSize()
 }
 
 func (t *Text) SetWidth(width int) {
+	if t.widthSet && t.width == width {
+		return
+	}
 	t.widthSet = true
 	t.width = width
+	t.resetCachedSize()
 }
 
 func (t *Text) SetHeight(height int) {
+	if t.heightSet && t.height == height {
+		return
+	}
 	t.heightSet = true
 	t.height = height
+	t.resetCachedSize()
 }
 
 func (t *Text) ResetSize() {
+	if !t.widthSet && !t.heightSet {
+		return
+	}
 	t.widthSet = false
 	t.heightSet = false
+	t.resetCachedSize()
 }
 
 func (t *Text) CursorShape(context *guigui.Context) (ebiten.CursorShapeType, bool) {
+	if !t.editable {
+		if _, ok := t.linkAt(context, context.Input().CursorPosition()); ok {
+			return ebiten.CursorShapePointer, true
+		}
+	}
 	if t.selectable || t.editable {
 		return ebiten.CursorShapeText, true
 	}
@@ -868,14 +1630,14 @@ func (t *Text) cursorPosition(context *guigui.Context) (x, top, bottom float64,
 		return 0, 0, 0, false
 	}
 
-	_, e, ok := t.selectionToDraw()
+	_, e, ok := t.selectionToDraw(context)
 	if !ok {
 		return 0, 0, 0, false
 	}
 
-	text := t.textToDraw()
+	text := t.textToDraw(context)
 	face := t.face(context)
-	return textPosition(textBounds, text, e, face, t.lineHeight(context), t.hAlign, t.vAlign)
+	return t.drawnTextPosition(context, textBounds, text, e, face, t.lineHeight(context))
 }
 
 func cursorWidth(context *guigui.Context) int {
@@ -934,7 +1696,7 @@ func (t *textCursor) shouldRenderCursor(context *guigui.Context, text *Text) boo
 	if _, _, _, ok := text.cursorPosition(context); !ok {
 		return false
 	}
-	s, e, ok := text.selectionToDraw()
+	s, e, ok := text.selectionToDraw(context)
 	if !ok {
 		return false
 	}
@@ -0,0 +1,408 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image/color"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"github.com/xackery/guigui"
+	"github.com/xackery/guigui/guiguitest"
+)
+
+func primaryKey() ebiten.Key {
+	if runtime.GOOS == "darwin" {
+		return ebiten.KeyMeta
+	}
+	return ebiten.KeyControl
+}
+
+// pressKeys presses the keys for the ticks, and then releases them.
+func pressKeys(t *testing.T, d *guiguitest.Driver, ticks int, keys ...ebiten.Key) {
+	t.Helper()
+	for _, k := range keys {
+		d.PressKey(k)
+	}
+	if err := d.StepN(ticks); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		d.ReleaseKey(k)
+	}
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTextShortcutAndCommand(t *testing.T) {
+	var text Text
+	text.SetEditable(true)
+	text.SetText("abc")
+	d := newTestDriver(&text)
+
+	var commandCount int
+	if err := d.Context().RegisterCommand(guigui.Command{
+		Name: "selectAll",
+		Action: func() {
+			commandCount++
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Context().BindKey(guigui.KeyChord{Key: ebiten.KeyA, Modifiers: guigui.ModifierPrimary}, "selectAll"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	guigui.Focus(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	text.setTextAndSelection(text.Text(), 1, 1, -1)
+
+	// One key stroke is handled by the focused text at the first tick, and is not dispatched to the command.
+	pressKeys(t, d, 5, primaryKey(), ebiten.KeyA)
+	if start, end := text.field.Selection(); start != 0 || end != 3 {
+		t.Errorf("Selection(): got: (%d, %d), want: (0, 3)", start, end)
+	}
+	if got, want := commandCount, 0; got != want {
+		t.Errorf("commandCount: got: %d, want: %d", got, want)
+	}
+
+	// Without the focus, the command is executed once.
+	guigui.Blur(&text)
+	pressKeys(t, d, 5, primaryKey(), ebiten.KeyA)
+	if got, want := commandCount, 1; got != want {
+		t.Errorf("commandCount: got: %d, want: %d", got, want)
+	}
+}
+
+func TestTextSelectableNotEditable(t *testing.T) {
+	var text Text
+	text.SetSelectable(true)
+	text.SetText("hello")
+	d := newTestDriver(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	text.setTextAndSelection(text.Text(), 0, 5, -1)
+	d.TypeText("X")
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text.Text(), "hello"; got != want {
+		t.Errorf("Text() after typing: got: %q, want: %q", got, want)
+	}
+}
+
+func TestTextHistory(t *testing.T) {
+	var text Text
+	text.SetEditable(true)
+	d := newTestDriver(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Successive typing is coalesced into one step.
+	for _, c := range "abc" {
+		d.TypeText(string(c))
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pressKeys(t, d, 1, primaryKey(), ebiten.KeyZ)
+	if got, want := text.Text(), ""; got != want {
+		t.Errorf("Text() after undo: got: %q, want: %q", got, want)
+	}
+	pressKeys(t, d, 1, primaryKey(), ebiten.KeyShift, ebiten.KeyZ)
+	if got, want := text.Text(), "abc"; got != want {
+		t.Errorf("Text() after redo: got: %q, want: %q", got, want)
+	}
+	if runtime.GOOS != "windows" {
+		// Ctrl+Y is redo only on Windows.
+		pressKeys(t, d, 1, primaryKey(), ebiten.KeyZ)
+		pressKeys(t, d, 1, ebiten.KeyControl, ebiten.KeyY)
+		if got, want := text.Text(), ""; got != want {
+			t.Errorf("Text() after Ctrl+Y: got: %q, want: %q", got, want)
+		}
+		pressKeys(t, d, 1, primaryKey(), ebiten.KeyShift, ebiten.KeyZ)
+	}
+
+	// Undo restores the selection replaced by typing.
+	text.setTextAndSelection("hello world", 0, 5, -1)
+	d.TypeText("X")
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text.Text(), "X world"; got != want {
+		t.Errorf("Text() after typing: got: %q, want: %q", got, want)
+	}
+	text.Undo()
+	if got, want := text.Text(), "hello world"; got != want {
+		t.Errorf("Text() after undo: got: %q, want: %q", got, want)
+	}
+	if start, end := text.field.Selection(); start != 0 || end != 5 {
+		t.Errorf("Selection() after undo: got: (%d, %d), want: (0, 5)", start, end)
+	}
+}
+
+func TestTextHistoryWithFilter(t *testing.T) {
+	var text Text
+	text.SetEditable(true)
+	text.SetText("12")
+	// Allow only digits.
+	text.SetFilter(func(text string, start, end int) (string, int, int) {
+		var digits []byte
+		for i := 0; i < len(text); i++ {
+			if text[i] >= '0' && text[i] <= '9' {
+				digits = append(digits, text[i])
+			}
+		}
+		return string(digits), min(start, len(digits)), min(end, len(digits))
+	})
+	d := newTestDriver(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	text.setTextAndSelection(text.Text(), 2, 2, -1)
+
+	// The filter is applied when the editing is committed.
+	d.TypeText("xy")
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	pressKeys(t, d, 2, ebiten.KeyBackspace)
+	if got, want := text.Text(), "12x"; got != want {
+		t.Errorf("Text() while editing: got: %q, want: %q", got, want)
+	}
+	guigui.Blur(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text.Text(), "12"; got != want {
+		t.Errorf("Text() after blur: got: %q, want: %q", got, want)
+	}
+
+	// Undoing outside of editing doesn't restore a text rejected by the filter.
+	if !text.CanUndo() {
+		t.Fatalf("CanUndo(): got: false, want: true")
+	}
+	text.Undo()
+	if got, want := text.Text(), "12"; got != want {
+		t.Errorf("Text() after undo: got: %q, want: %q", got, want)
+	}
+}
+
+func TestTextContextMenuLabels(t *testing.T) {
+	var text Text
+	text.SetEditable(true)
+	text.SetText("abc")
+	d := newTestDriver(&text)
+	SetTextContextMenuLabels(d.Context(), TextContextMenuLabels{
+		Cut:       "Ausschneiden",
+		SelectAll: "Alles auswählen",
+	})
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressKeys(t, d, 1, ebiten.KeyShift, ebiten.KeyF10)
+	if text.contextMenu == nil {
+		t.Fatal("the context menu is not opened")
+	}
+	var got []string
+	for _, item := range text.contextMenu.popupMenu.items {
+		got = append(got, item.Text)
+	}
+	// An empty label falls back to the default label.
+	want := []string{"Ausschneiden", "Copy", "Paste", "Alles auswählen"}
+	if !slices.Equal(got, want) {
+		t.Errorf("items: got: %q, want: %q", got, want)
+	}
+}
+
+func TestTextContextMenuOpensAtFirstClick(t *testing.T) {
+	var text Text
+	text.SetEditable(true)
+	text.SetText("abc")
+	d := newTestDriver(&text)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := guigui.Position(&text)
+	d.MoveCursor(p.X+1, p.Y+1)
+	d.PressMouseButton(ebiten.MouseButtonRight)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	d.ReleaseMouseButton(ebiten.MouseButtonRight)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if text.contextMenu == nil || !guigui.IsVisible(&text.contextMenu.popupMenu.popup) {
+		t.Errorf("the context menu is not visible after the first right click")
+	}
+}
+
+func TestTextStyledRunsMerge(t *testing.T) {
+	bold := TextStyle{Weight: text.WeightBold}
+	red := TextStyle{Color: color.RGBA{R: 0xff, A: 0xff}}
+	var txt Text
+	txt.SetStyledText([]TextRun{
+		{Text: "ab", Style: bold},
+		{Text: "cd", Style: bold},
+		{Text: "", Style: TextStyle{Underline: true}},
+		{Text: "ef"},
+		{Text: "gh", Style: red},
+		{Text: "ij", Style: TextStyle{Color: color.NRGBA{R: 0xff, A: 0xff}}},
+	})
+	want := []textSpan{
+		{start: 0, end: 4, style: bold},
+		{start: 4, end: 6},
+		{start: 6, end: 10, style: red},
+	}
+	if got := txt.spans; !slices.EqualFunc(got, want, equalTextSpan) {
+		t.Errorf("spans: got: %v, want: %v", got, want)
+	}
+}
+
+func equalTextSpan(a, b textSpan) bool {
+	return a.start == b.start && a.end == b.end && equalTextStyle(a.style, b.style)
+}
+
+func TestTextStyledSpansAfterEdit(t *testing.T) {
+	bold := TextStyle{Weight: text.WeightBold}
+	underline := TextStyle{Underline: true}
+
+	testCases := []struct {
+		name      string
+		start     int
+		end       int
+		typed     string
+		backspace bool
+		wantText  string
+		wantSpans []textSpan
+	}{
+		{
+			name:     "insert at a boundary",
+			start:    2,
+			end:      2,
+			typed:    "X",
+			wantText: "abXcdef",
+			// The inserted text takes the style of the preceding character.
+			wantSpans: []textSpan{{0, 3, bold}, {3, 5, underline}, {5, 7, bold}},
+		},
+		{
+			name:      "insert the same character as the following one",
+			start:     2,
+			end:       2,
+			typed:     "c",
+			wantText:  "abccdef",
+			wantSpans: []textSpan{{0, 3, bold}, {3, 5, underline}, {5, 7, bold}},
+		},
+		{
+			name:      "insert at the beginning",
+			start:     0,
+			end:       0,
+			typed:     "X",
+			wantText:  "Xabcdef",
+			wantSpans: []textSpan{{0, 3, bold}, {3, 5, underline}, {5, 7, bold}},
+		},
+		{
+			name:      "delete in a span",
+			start:     4,
+			end:       4,
+			backspace: true,
+			wantText:  "abcef",
+			wantSpans: []textSpan{{0, 2, bold}, {2, 3, underline}, {3, 5, bold}},
+		},
+		{
+			name:      "delete a span",
+			start:     2,
+			end:       4,
+			backspace: true,
+			wantText:  "abef",
+			// The adjacent spans with the same style are merged.
+			wantSpans: []textSpan{{0, 4, bold}},
+		},
+		{
+			name:      "replace across spans",
+			start:     1,
+			end:       5,
+			typed:     "X",
+			wantText:  "aXf",
+			wantSpans: []textSpan{{0, 3, bold}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var txt Text
+			txt.SetEditable(true)
+			txt.SetStyledText([]TextRun{
+				{Text: "ab", Style: bold},
+				{Text: "cd", Style: underline},
+				{Text: "ef", Style: bold},
+			})
+			d := newTestDriver(&txt)
+			if err := d.Step(); err != nil {
+				t.Fatal(err)
+			}
+			guigui.Focus(&txt)
+			if err := d.Step(); err != nil {
+				t.Fatal(err)
+			}
+
+			txt.setTextAndSelection(txt.Text(), tc.start, tc.end, -1)
+			if tc.backspace {
+				pressKeys(t, d, 2, ebiten.KeyBackspace)
+			} else {
+				d.TypeText(tc.typed)
+				if err := d.Step(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := txt.Text(); got != tc.wantText {
+				t.Errorf("Text(): got: %q, want: %q", got, tc.wantText)
+			}
+			if got := txt.spans; !slices.EqualFunc(got, tc.wantSpans, equalTextSpan) {
+				t.Errorf("spans: got: %v, want: %v", got, tc.wantSpans)
+			}
+			if !txt.isStyled() {
+				t.Errorf("isStyled(): got: false, want: true")
+			}
+
+			// Undo restores the spans.
+			txt.Undo()
+			want := []textSpan{{0, 2, bold}, {2, 4, underline}, {4, 6, bold}}
+			if got := txt.spans; !slices.EqualFunc(got, want, equalTextSpan) {
+				t.Errorf("spans after undo: got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
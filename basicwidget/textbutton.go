@@ -36,8 +36,18 @@ func (t *TextButton) SetText(text string) {
 	t.text.SetText(text)
 }
 
+// SetTooltip sets the text shown when the cursor stays on the button.
+func (t *TextButton) SetTooltip(text string) {
+	t.button.SetTooltip(text)
+}
+
 func (t *TextButton) SetImage(image *ebiten.Image) {
+	hadImage := t.image.HasImage()
 	t.image.SetImage(image)
+	// The layout and the size depend on whether the image exists.
+	if t.image.HasImage() != hadImage {
+		guigui.RequestRelayout(t)
+	}
 }
 
 func (t *TextButton) SetTextColor(clr color.Color) {
@@ -56,7 +66,15 @@ func (t *TextButton) Layout(context *guigui.Context, appender *guigui.ChildWidge
 
 	imgSize := int(LineHeight(context))
 
+	// Truncate the text when the button is narrower than the text.
 	tw, _ := t.text.TextSize(context)
+	if t.image.HasImage() {
+		tw = min(tw, w-t.textImagePadding(context)-imgSize-UnitSize(context)*3/4)
+	} else {
+		tw = min(tw, w-UnitSize(context))
+	}
+	tw = max(tw, 0)
+	t.text.SetTruncation(TextTruncationEnd)
 	t.text.SetSize(tw, h)
 	textP := guigui.Position(t)
 	if t.image.HasImage() {
@@ -95,6 +113,19 @@ func (t *TextButton) Update(context *guigui.Context) error {
 	return nil
 }
 
+func (t *TextButton) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role:    guigui.AccessibilityRoleButton,
+		Label:   t.text.Text(),
+		Actions: []guigui.AccessibilityAction{guigui.AccessibilityActionPress},
+		Leaf:    true,
+	}
+}
+
+func (t *TextButton) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	return t.button.PerformAccessibilityAction(context, action)
+}
+
 func (t *TextButton) Size(context *guigui.Context) (int, int) {
 	_, dh := defaultButtonSize(context)
 	if t.widthSet {
@@ -109,13 +140,21 @@ func (t *TextButton) Size(context *guigui.Context) (int, int) {
 }
 
 func (t *TextButton) SetWidth(width int) {
+	if t.widthSet && t.width == width {
+		return
+	}
 	t.width = width
 	t.widthSet = true
+	guigui.RequestRelayout(t)
 }
 
 func (t *TextButton) ResetWidth() {
+	if !t.widthSet {
+		return
+	}
 	t.width = 0
 	t.widthSet = false
+	guigui.RequestRelayout(t)
 }
 
 func (t *TextButton) textImagePadding(context *guigui.Context) int {
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"log/slog"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+// TextContextMenuLabels is the labels of the items added to the context menus of texts.
+// An empty label means the default English label.
+type TextContextMenuLabels struct {
+	Cut       string
+	Copy      string
+	Paste     string
+	SelectAll string
+}
+
+type textContextMenuLabelsKey struct{}
+
+// SetTextContextMenuLabels sets the labels of the items added to the context menus of the texts in the app,
+// e.g. to localize them.
+func SetTextContextMenuLabels(context *guigui.Context, labels TextContextMenuLabels) {
+	context.SetValue(textContextMenuLabelsKey{}, labels)
+}
+
+func textContextMenuLabelsFromContext(context *guigui.Context) TextContextMenuLabels {
+	labels, _ := context.Value(textContextMenuLabelsKey{}).(TextContextMenuLabels)
+	if labels.Cut == "" {
+		labels.Cut = "Cut"
+	}
+	if labels.Copy == "" {
+		labels.Copy = "Copy"
+	}
+	if labels.Paste == "" {
+		labels.Paste = "Paste"
+	}
+	if labels.SelectAll == "" {
+		labels.SelectAll = "Select All"
+	}
+	return labels
+}
+
+// SetOnContextMenu sets the function to return the items of the context menu.
+// The function is called when the text is right-clicked, or the Menu key or Shift+F10 is pressed while the text is focused.
+// position is the position to open the menu.
+//
+// For an editable text, the items to cut, copy, paste and select the text are added before the returned items.
+// For a selectable text, the items to copy and select the text are added.
+// The labels of the added items can be changed by SetTextContextMenuLabels.
+func (t *Text) SetOnContextMenu(f func(position image.Point) []ContextMenuItem) {
+	t.onContextMenu = f
+}
+
+func (t *Text) handleContextMenuInput(context *guigui.Context) bool {
+	if !t.selectable && !t.editable && t.onContextMenu == nil {
+		return false
+	}
+
+	input := context.Input()
+	textBounds := t.textBounds(context)
+	face := t.face(context)
+	lh := t.lineHeight(context)
+
+	var position image.Point
+	switch {
+	case input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && input.CursorPosition().In(guigui.VisibleBounds(t)):
+		position = input.CursorPosition()
+		if t.selectable || t.editable {
+			guigui.Focus(t)
+			// Keep the selection when the selection is clicked so that the selected text can be cut or copied.
+			idx := t.textIndexFromPosition(context, textBounds, position, face, lh)
+			if start, end := t.field.Selection(); idx < start || idx > end {
+				t.setTextAndSelection(t.field.Text(), idx, idx, -1)
+			}
+		}
+	case guigui.IsFocused(t) && isContextMenuKeyJustPressed(input):
+		// Open the menu below the cursor.
+		position = guigui.Position(t)
+		start, _ := t.field.Selection()
+		if x, _, bottom, ok := t.textPosition(context, textBounds, start, face, lh); ok {
+			position = image.Pt(int(x), int(bottom))
+		}
+	default:
+		return false
+	}
+
+	if t.contextMenu == nil {
+		t.contextMenu = &contextMenu{}
+	}
+	return t.contextMenu.open(context, position, t.contextMenuItems(context, position))
+}
+
+func (t *Text) contextMenuItems(context *guigui.Context, position image.Point) []ContextMenuItem {
+	labels := textContextMenuLabelsFromContext(context)
+	var items []ContextMenuItem
+	if t.editable {
+		items = append(items, ContextMenuItem{
+			Text:     labels.Cut,
+			Disabled: !t.canCopy(),
+			OnSelected: func() {
+				t.editByContextMenu(t.cut)
+			},
+		})
+	}
+	if t.editable || t.selectable {
+		items = append(items, ContextMenuItem{
+			Text:     labels.Copy,
+			Disabled: !t.canCopy(),
+			OnSelected: func() {
+				t.editByContextMenu(t.copy)
+			},
+		})
+	}
+	if t.editable {
+		items = append(items, ContextMenuItem{
+			Text: labels.Paste,
+			OnSelected: func() {
+				t.editByContextMenu(t.paste)
+			},
+		})
+	}
+	if t.editable || t.selectable {
+		items = append(items, ContextMenuItem{
+			Text:     labels.SelectAll,
+			Disabled: t.field.Text() == "",
+			OnSelected: func() {
+				t.editByContextMenu(func() error {
+					t.selectAll()
+					return nil
+				})
+			},
+		})
+	}
+
+	if t.onContextMenu != nil {
+		if extra := t.onContextMenu(position); len(extra) > 0 {
+			if len(items) > 0 {
+				items = append(items, ContextMenuItem{Border: true})
+			}
+			items = append(items, extra...)
+		}
+	}
+	return items
+}
+
+// editByContextMenu performs the edit selected in the context menu.
+// The focus is moved back to the text as the menu takes the focus.
+func (t *Text) editByContextMenu(edit func() error) {
+	if t.selectable || t.editable {
+		guigui.Focus(t)
+	}
+	before := t.editState()
+	if err := edit(); err != nil {
+		slog.Error(err.Error())
+		return
+	}
+	if t.field.Text() != before.text {
+		t.enforceMaxLength(before)
+		t.history.record(before, t.editState(), textEditKindOther)
+	}
+}
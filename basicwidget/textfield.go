@@ -7,27 +7,115 @@ import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/xackery/guigui"
 )
 
 type TextField struct {
 	guigui.DefaultWidget
 
-	text  Text
-	focus textFieldFocus
+	text        Text
+	placeholder Text
+	focus       textFieldFocus
+	message     textFieldMessage
+	reveal      textFieldRevealButton
 
 	widthMinusDefault  int
 	heightMinusDefault int
 
-	hovering bool
-	readonly bool
+	hovering   bool
+	readonly   bool
+	masked     bool
+	revealable bool
+	revealed   bool
 
-	prevFocused bool
+	// paddingEnd is the width reserved for widgets overlapping the end of the text field, like NumberInput's buttons.
+	paddingEnd int
+
+	validator     func(text string) error
+	err           error
+	validatedText string
+
+	onEnterPressed func(text string)
 }
 
 func (t *TextField) SetOnEnterPressed(f func(text string)) {
-	t.text.SetOnEnterPressed(f)
+	t.onEnterPressed = f
+}
+
+// SetPlaceholder sets the text shown when the text field is empty.
+func (t *TextField) SetPlaceholder(placeholder string) {
+	t.placeholder.SetText(placeholder)
+}
+
+// SetMasked sets whether the text is masked, e.g. for a password.
+func (t *TextField) SetMasked(masked bool) {
+	if t.masked == masked {
+		return
+	}
+	t.masked = masked
+	guigui.RequestRelayout(t)
+}
+
+// SetRevealable sets whether a button to reveal the masked text is shown.
+func (t *TextField) SetRevealable(revealable bool) {
+	if t.revealable == revealable {
+		return
+	}
+	t.revealable = revealable
+	if !revealable {
+		t.revealed = false
+	}
+	guigui.RequestRelayout(t)
+}
+
+// SetMaxLength sets the maximum length of the text in the unit.
+// If maxLength is 0 or less, the length is not limited.
+func (t *TextField) SetMaxLength(maxLength int, unit TextLengthUnit) {
+	t.text.SetMaxLength(maxLength, unit)
+}
+
+func (t *TextField) SetFilter(filter TextFilter) {
+	t.text.SetFilter(filter)
+}
+
+// SetValidator sets the function to validate the text.
+//
+// The text is validated when the editing is committed, i.e. when the text field loses the focus or Enter is pressed.
+// While the text is invalid, the text is validated at every change.
+// An invalid text field shows the error message with a danger-colored border.
+func (t *TextField) SetValidator(validator func(text string) error) {
+	t.validator = validator
+	// Revalidate the text so that the shown error doesn't come from the old validator.
+	if t.err != nil {
+		t.Validate()
+	}
+}
+
+// Validate validates the text and returns the error.
+func (t *TextField) Validate() error {
+	if t.validator == nil {
+		t.setError(nil)
+		return nil
+	}
+	t.validatedText = t.text.Text()
+	t.setError(t.validator(t.validatedText))
+	return t.err
+}
+
+// ValidationError returns the error of the last validation.
+func (t *TextField) ValidationError() error {
+	return t.err
+}
+
+func (t *TextField) setError(err error) {
+	if t.err == err {
+		return
+	}
+	t.err = err
+	guigui.RequestRedraw(t)
+	// The error message is shown or hidden.
+	guigui.RequestRelayout(t)
 }
 
 func (t *TextField) Text() string {
@@ -42,6 +130,10 @@ func (t *TextField) SetMultiline(multiline bool) {
 	t.text.SetMultiline(multiline)
 }
 
+func (t *TextField) SetAutoWrap(autoWrap bool) {
+	t.text.SetAutoWrap(autoWrap)
+}
+
 func (t *TextField) SetHorizontalAlign(halign HorizontalAlign) {
 	t.text.SetHorizontalAlign(halign)
 }
@@ -55,36 +147,90 @@ func (t *TextField) SetEditable(editable bool) {
 	t.readonly = !editable
 }
 
+func (t *TextField) CanUndo() bool {
+	return t.text.CanUndo()
+}
+
+func (t *TextField) CanRedo() bool {
+	return t.text.CanRedo()
+}
+
+func (t *TextField) Undo() {
+	t.text.Undo()
+}
+
+func (t *TextField) Redo() {
+	t.text.Redo()
+}
+
 func (t *TextField) SelectAll() {
 	t.text.selectAll()
 }
 
 func (t *TextField) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
 	t.text.SetEditable(true)
+	t.text.SetMasked(t.masked && !t.revealed)
 	b := guigui.Bounds(t)
 	b.Min.X += UnitSize(context) / 2
 	b.Max.X -= UnitSize(context) / 2
+	b.Max.X -= t.paddingEnd
+	if t.masked && t.revealable {
+		s := int(LineHeight(context))
+		b.Max.X -= s
+		t.reveal.textField = t
+		guigui.SetPosition(&t.reveal, image.Pt(b.Max.X, guigui.Position(t).Y+(guigui.Bounds(t).Dy()-s)/2))
+	}
 	t.text.SetSize(b.Dx(), b.Dy())
 	// TODO: Consider multiline.
 	if !t.text.IsMultiline() {
 		t.text.SetVerticalAlign(VerticalAlignMiddle)
 	}
+	t.text.SetOnEnterPressed(func(text string) {
+		t.Validate()
+		if t.onEnterPressed != nil {
+			t.onEnterPressed(text)
+		}
+	})
+
+	if t.text.Text() == "" {
+		t.placeholder.SetSize(b.Dx(), b.Dy())
+		t.placeholder.SetMultiline(t.text.IsMultiline())
+		t.placeholder.SetHorizontalAlign(t.text.hAlign)
+		t.placeholder.SetVerticalAlign(t.text.vAlign)
+		t.placeholder.SetColor(Color(context.ColorMode(), ColorTypeBase, 0.5))
+		guigui.SetPosition(&t.placeholder, b.Min)
+		appender.AppendChildWidget(&t.placeholder)
+	}
+
 	guigui.SetPosition(&t.text, b.Min)
 	appender.AppendChildWidget(&t.text)
 
+	if t.masked && t.revealable {
+		appender.AppendChildWidget(&t.reveal)
+	}
+
 	if guigui.HasFocusedChildWidget(t) {
 		w := textFieldFocusBorderWidth(context)
 		p := guigui.Position(t).Add(image.Pt(-w, -w))
 		guigui.SetPosition(&t.focus, p)
 		appender.AppendChildWidget(&t.focus)
 	}
+
+	if t.err != nil {
+		t.message.text.SetText(t.err.Error())
+		t.message.text.SetColor(Color(context.ColorMode(), ColorTypeDanger, 0.5))
+		p := guigui.Position(t)
+		_, h := t.Size(context)
+		p.Y += h + textFieldFocusBorderWidth(context)
+		guigui.SetPosition(&t.message, p)
+		appender.AppendChildWidget(&t.message)
+	}
 }
 
 func (t *TextField) HandleInput(context *guigui.Context) guigui.HandleInputResult {
-	x, y := ebiten.CursorPosition()
-	t.hovering = image.Pt(x, y).In(guigui.VisibleBounds(t))
+	t.hovering = context.Input().CursorPosition().In(guigui.VisibleBounds(t))
 	if t.hovering {
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if context.Input().IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 			guigui.Focus(&t.text)
 			t.text.selectAll()
 			return guigui.HandleInputByWidget(t)
@@ -94,21 +240,56 @@ func (t *TextField) HandleInput(context *guigui.Context) guigui.HandleInputResul
 }
 
 func (t *TextField) Update(context *guigui.Context) error {
-	if t.prevFocused != guigui.HasFocusedChildWidget(t) {
-		t.prevFocused = guigui.HasFocusedChildWidget(t)
-		guigui.RequestRedraw(t)
-	}
 	if guigui.IsFocused(t) {
 		guigui.Focus(&t.text)
 		guigui.RequestRedraw(t)
 	}
+	// Validate the text at every change while the text is invalid, so that the error disappears as soon as it is fixed.
+	if t.err != nil && t.validatedText != t.text.Text() {
+		t.Validate()
+	}
 	return nil
 }
 
+func (t *TextField) OnFocusChanged(context *guigui.Context, event guigui.FocusChangedEvent) {
+	if !event.FocusWithin {
+		t.Validate()
+	}
+	guigui.RequestRedraw(t)
+}
+
+func (t *TextField) Accessibility(context *guigui.Context) guigui.Accessibility {
+	value := t.text.Text()
+	if t.masked && !t.revealed {
+		// Don't rely on t.text's mask, which is updated only at Layout.
+		value = t.text.maskedTextCache.get(t.text.field.TextForRendering()).masked
+	}
+	return guigui.Accessibility{
+		Role:      guigui.AccessibilityRoleTextField,
+		Value:     value,
+		ReadOnly:  t.readonly,
+		Multiline: t.text.IsMultiline(),
+		Actions:   []guigui.AccessibilityAction{guigui.AccessibilityActionFocus},
+		Leaf:      true,
+	}
+}
+
+func (t *TextField) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionFocus {
+		return false
+	}
+	guigui.Focus(&t.text)
+	return guigui.IsFocused(&t.text)
+}
+
 func (t *TextField) Draw(context *guigui.Context, dst *ebiten.Image) {
 	bounds := guigui.Bounds(t)
 	DrawRoundedRect(context, dst, bounds, Color(context.ColorMode(), ColorTypeBase, 0.85), RoundedCornerRadius(context))
-	DrawRoundedRectBorder(context, dst, bounds, Color2(context.ColorMode(), ColorTypeBase, 0.7, 0), RoundedCornerRadius(context), float32(1*context.Scale()), RoundedRectBorderTypeInset)
+	borderColor := Color2(context.ColorMode(), ColorTypeBase, 0.7, 0)
+	if t.err != nil {
+		borderColor = Color(context.ColorMode(), ColorTypeDanger, 0.5)
+	}
+	DrawRoundedRectBorder(context, dst, bounds, borderColor, RoundedCornerRadius(context), float32(1*context.Scale()), RoundedRectBorderTypeInset)
 }
 
 func defaultTextFieldSize(context *guigui.Context) (int, int) {
@@ -118,8 +299,12 @@ func defaultTextFieldSize(context *guigui.Context) (int, int) {
 
 func (t *TextField) SetSize(context *guigui.Context, width, height int) {
 	dw, dh := defaultTextFieldSize(context)
+	if t.widthMinusDefault == width-dw && t.heightMinusDefault == height-dh {
+		return
+	}
 	t.widthMinusDefault = width - dw
 	t.heightMinusDefault = height - dh
+	guigui.RequestRelayout(t)
 }
 
 func (t *TextField) Size(context *guigui.Context) (int, int) {
@@ -143,7 +328,11 @@ func (t *textFieldFocus) Draw(context *guigui.Context, dst *ebiten.Image) {
 	bounds := guigui.Bounds(textField)
 	w := textFieldFocusBorderWidth(context)
 	bounds = bounds.Inset(-w)
-	DrawRoundedRectBorder(context, dst, bounds, Color(context.ColorMode(), ColorTypeAccent, 0.8), int(4*context.Scale())+RoundedCornerRadius(context), float32(4*context.Scale()), RoundedRectBorderTypeRegular)
+	clr := Color(context.ColorMode(), ColorTypeAccent, 0.8)
+	if textField.err != nil {
+		clr = Color(context.ColorMode(), ColorTypeDanger, 0.8)
+	}
+	DrawRoundedRectBorder(context, dst, bounds, clr, int(4*context.Scale())+RoundedCornerRadius(context), float32(4*context.Scale()), RoundedRectBorderTypeRegular)
 }
 
 func (t *textFieldFocus) IsPopup() bool {
@@ -156,3 +345,88 @@ func (t *textFieldFocus) Size(context *guigui.Context) (int, int) {
 	h += 2 * textFieldFocusBorderWidth(context)
 	return w, h
 }
+
+type textFieldMessage struct {
+	guigui.DefaultWidget
+
+	text Text
+}
+
+func (t *textFieldMessage) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetPosition(&t.text, guigui.Position(t))
+	appender.AppendChildWidget(&t.text)
+}
+
+func (t *textFieldMessage) IsPopup() bool {
+	// The message is shown outside of the text field.
+	return true
+}
+
+func (t *textFieldMessage) Size(context *guigui.Context) (int, int) {
+	return t.text.Size(context)
+}
+
+type textFieldRevealButton struct {
+	guigui.DefaultWidget
+
+	mouseOverlay guigui.MouseOverlay
+
+	textField *TextField
+}
+
+func (t *textFieldRevealButton) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	t.mouseOverlay.SetOnUp(func(mouseButton ebiten.MouseButton, cursorPosition image.Point) {
+		if mouseButton != ebiten.MouseButtonLeft {
+			return
+		}
+		t.textField.revealed = !t.textField.revealed
+		guigui.Focus(&t.textField.text)
+		guigui.RequestRedraw(t)
+		// The text's mask is updated at the text field's layout.
+		guigui.RequestRelayout(t.textField)
+	})
+	guigui.SetPosition(&t.mouseOverlay, guigui.Position(t))
+	appender.AppendChildWidget(&t.mouseOverlay)
+}
+
+func (t *textFieldRevealButton) CursorShape(context *guigui.Context) (ebiten.CursorShapeType, bool) {
+	if guigui.IsEnabled(t) && t.mouseOverlay.IsHovering() {
+		return ebiten.CursorShapePointer, true
+	}
+	return 0, false
+}
+
+func (t *textFieldRevealButton) Draw(context *guigui.Context, dst *ebiten.Image) {
+	// Draw an eye. The eye is crossed out while the text is masked.
+	bounds := guigui.Bounds(t)
+	cx := float32(bounds.Min.X) + float32(bounds.Dx())/2
+	cy := float32(bounds.Min.Y) + float32(bounds.Dy())/2
+	w := float32(bounds.Dx()) * 0.7
+	h := float32(bounds.Dy()) * 0.45
+	strokeWidth := float32(1.5 * context.Scale())
+
+	clr := Color(context.ColorMode(), ColorTypeBase, 0.4)
+	if t.mouseOverlay.IsHovering() {
+		clr = Color(context.ColorMode(), ColorTypeBase, 0.2)
+	}
+
+	var path vector.Path
+	path.MoveTo(cx-w/2, cy)
+	path.QuadTo(cx, cy-h, cx+w/2, cy)
+	path.QuadTo(cx, cy+h, cx-w/2, cy)
+	path.Close()
+	vector.StrokePath(dst, &path, clr, true, &vector.StrokeOptions{
+		Width:    strokeWidth,
+		LineJoin: vector.LineJoinRound,
+	})
+	vector.DrawFilledCircle(dst, cx, cy, h/4, clr, true)
+
+	if !t.textField.revealed {
+		vector.StrokeLine(dst, cx-w/2, cy+h/2+strokeWidth, cx+w/2, cy-h/2-strokeWidth, strokeWidth, clr, true)
+	}
+}
+
+func (t *textFieldRevealButton) Size(context *guigui.Context) (int, int) {
+	s := int(LineHeight(context))
+	return s, s
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func containsInAccessibilitySnapshot(node guigui.AccessibilityNode, str string) bool {
+	_, ok := findAccessibilityNode(node, func(node *guigui.AccessibilityNode) bool {
+		return strings.Contains(node.Label, str) || strings.Contains(node.Value, str)
+	})
+	return ok
+}
+
+func TestTextFieldMaskedAccessibility(t *testing.T) {
+	const password = "secret"
+
+	var textField TextField
+	textField.SetText(password)
+	d := newTestDriver(&textField)
+
+	// The mask must be hidden even before the text field is laid out with the mask.
+	textField.SetMasked(true)
+	if containsInAccessibilitySnapshot(d.Context().AccessibilitySnapshot(), password) {
+		t.Errorf("the snapshot contains the masked text before Step")
+	}
+
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if !textField.text.IsMasked() {
+		t.Errorf("IsMasked(): got: false, want: true")
+	}
+	if containsInAccessibilitySnapshot(d.Context().AccessibilitySnapshot(), password) {
+		t.Errorf("the snapshot contains the masked text")
+	}
+
+	// Type characters with the focus.
+	guigui.Focus(&textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	d.TypeText("123")
+	if err := d.StepN(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := textField.Text(); !strings.Contains(got, "123") {
+		t.Errorf("Text(): got: %q, want: containing %q", got, "123")
+	}
+	if containsInAccessibilitySnapshot(d.Context().AccessibilitySnapshot(), "123") {
+		t.Errorf("the snapshot contains the masked text after typing")
+	}
+
+	// The text is exposed only after it is unmasked. Unmasking must be applied without any input.
+	textField.SetMasked(false)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if textField.text.IsMasked() {
+		t.Errorf("IsMasked(): got: true, want: false")
+	}
+	if !containsInAccessibilitySnapshot(d.Context().AccessibilitySnapshot(), password) {
+		t.Errorf("the snapshot doesn't contain the unmasked text")
+	}
+}
+
+func TestTextFieldPlaceholderMaxLengthAndValidation(t *testing.T) {
+	var textField TextField
+	textField.SetPlaceholder("Name")
+	textField.SetMaxLength(3, TextLengthUnitRune)
+	errDigit := errors.New("digits are not allowed")
+	textField.SetValidator(func(text string) error {
+		if strings.ContainsAny(text, "0123456789") {
+			return errDigit
+		}
+		return nil
+	})
+	var other TextField
+	d := newTestDriver(&textField, &other)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(d.Widgets(), guigui.Widget(&textField.placeholder)) {
+		t.Errorf("the placeholder is not shown for an empty text")
+	}
+
+	// The typed text is truncated by the maximum length, and the placeholder is hidden.
+	guigui.Focus(&textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	d.TypeText("ab1cd")
+	if err := d.StepN(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := textField.Text(), "ab1"; got != want {
+		t.Errorf("Text(): got: %q, want: %q", got, want)
+	}
+	if slices.Contains(d.Widgets(), guigui.Widget(&textField.placeholder)) {
+		t.Errorf("the placeholder is shown for a non-empty text")
+	}
+
+	// The text is not validated until the editing is committed.
+	if err := textField.ValidationError(); err != nil {
+		t.Errorf("ValidationError() before committing: got: %v, want: nil", err)
+	}
+	pressKeys(t, d, 1, ebiten.KeyTab)
+	if err := textField.ValidationError(); err != errDigit {
+		t.Errorf("ValidationError() after blur: got: %v, want: %v", err, errDigit)
+	}
+	if !slices.Contains(d.Widgets(), guigui.Widget(&textField.message)) {
+		t.Errorf("the error message is not shown")
+	}
+
+	// While the text is invalid, the text is validated at every change.
+	guigui.Focus(&textField)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	pressKeys(t, d, 2, ebiten.KeyBackspace)
+	if got, want := textField.Text(), "ab"; got != want {
+		t.Errorf("Text() after Backspace: got: %q, want: %q", got, want)
+	}
+	if err := textField.ValidationError(); err != nil {
+		t.Errorf("ValidationError() after fixing: got: %v, want: nil", err)
+	}
+	if slices.Contains(d.Widgets(), guigui.Widget(&textField.message)) {
+		t.Errorf("the error message is shown after fixing")
+	}
+
+	if _, ok := findAccessibilityNode(d.Context().AccessibilitySnapshot(), func(node *guigui.AccessibilityNode) bool {
+		return node.Role == guigui.AccessibilityRoleTextField && node.Value == "ab" && node.Focused
+	}); !ok {
+		t.Errorf("no focused text field node with the value %q", "ab")
+	}
+}
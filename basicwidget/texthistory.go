@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"slices"
+	"time"
+)
+
+type textEditKind int
+
+const (
+	textEditKindOther textEditKind = iota
+	textEditKindTyping
+	textEditKindDeleting
+)
+
+// textHistoryMaxCount is the maximum number of undo steps.
+const textHistoryMaxCount = 1000
+
+// textHistoryCoalescingDuration is the maximum interval of edits coalesced into one undo step.
+const textHistoryCoalescingDuration = time.Second
+
+type textEditState struct {
+	text  string
+	start int
+	end   int
+
+	// spans is the styled spans of the text. spans is nil for a text without styles.
+	spans []textSpan
+}
+
+// sameTextAndSelection reports whether the two states have the same text and selection.
+// The spans are not compared, as they follow the text.
+func (s *textEditState) sameTextAndSelection(other *textEditState) bool {
+	return s.text == other.text && s.start == other.start && s.end == other.end
+}
+
+type textHistory struct {
+	undoStates []textEditState
+	redoStates []textEditState
+
+	lastKind  textEditKind
+	lastState textEditState
+	lastTime  time.Time
+}
+
+func (t *textHistory) reset() {
+	t.undoStates = slices.Delete(t.undoStates, 0, len(t.undoStates))
+	t.redoStates = slices.Delete(t.redoStates, 0, len(t.redoStates))
+	t.lastKind = textEditKindOther
+}
+
+// record records an edit from before to after.
+//
+// Successive edits of the same kind are coalesced into one step
+// when there is no other change between them and they are made in a short time.
+func (t *textHistory) record(before, after textEditState, kind textEditKind) {
+	now := time.Now()
+	coalesce := kind != textEditKindOther && kind == t.lastKind && before.sameTextAndSelection(&t.lastState) && now.Sub(t.lastTime) < textHistoryCoalescingDuration && len(t.undoStates) > 0
+	if !coalesce {
+		t.undoStates = append(t.undoStates, before)
+		if len(t.undoStates) > textHistoryMaxCount {
+			t.undoStates = slices.Delete(t.undoStates, 0, len(t.undoStates)-textHistoryMaxCount)
+		}
+	}
+	t.redoStates = slices.Delete(t.redoStates, 0, len(t.redoStates))
+
+	t.lastKind = kind
+	t.lastState = after
+	t.lastTime = now
+}
+
+func (t *textHistory) canUndo() bool {
+	return len(t.undoStates) > 0
+}
+
+func (t *textHistory) canRedo() bool {
+	return len(t.redoStates) > 0
+}
+
+func (t *textHistory) undo(current textEditState) (textEditState, bool) {
+	if len(t.undoStates) == 0 {
+		return textEditState{}, false
+	}
+	state := t.undoStates[len(t.undoStates)-1]
+	t.undoStates = t.undoStates[:len(t.undoStates)-1]
+	t.redoStates = append(t.redoStates, current)
+	t.lastKind = textEditKindOther
+	return state, true
+}
+
+func (t *textHistory) redo(current textEditState) (textEditState, bool) {
+	if len(t.redoStates) == 0 {
+		return textEditState{}, false
+	}
+	state := t.redoStates[len(t.redoStates)-1]
+	t.redoStates = t.redoStates[:len(t.redoStates)-1]
+	t.undoStates = append(t.undoStates, current)
+	t.lastKind = textEditKindOther
+	return state, true
+}
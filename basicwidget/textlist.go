@@ -38,6 +38,9 @@ type TextListItem struct {
 	Border    bool
 	Draggable bool
 	Tag       any
+
+	// Tooltip is the text shown when the cursor stays on the item.
+	Tooltip string
 }
 
 func (t *TextListItem) selectable() bool {
@@ -90,6 +93,12 @@ func (t *TextList) Layout(context *guigui.Context, appender *guigui.ChildWidgetA
 	appender.AppendChildWidget(&t.list)
 }
 
+// SetOnContextMenu sets the function to return the items of the context menu.
+// See also List.SetOnContextMenu.
+func (t *TextList) SetOnContextMenu(f func(index int, position image.Point) []ContextMenuItem) {
+	t.list.SetOnContextMenu(f)
+}
+
 func (t *TextList) SelectedItemIndex() int {
 	return t.list.SelectedItemIndex()
 }
@@ -130,7 +139,7 @@ func (t *TextList) SetItems(items []TextListItem) {
 				textListItem: item,
 			}
 		} else {
-			t.textListItemWidgets[i].textListItem = item
+			t.textListItemWidgets[i].setTextListItem(item)
 		}
 		listItems[i] = t.textListItemWidgets[i].listItem()
 	}
@@ -158,7 +167,9 @@ func (t *TextList) SetStyle(style ListStyle) {
 }
 
 func (t *TextList) SetItemString(str string, index int) {
-	t.textListItemWidgets[index].textListItem.Text = str
+	item := t.textListItemWidgets[index].textListItem
+	item.Text = str
+	t.textListItemWidgets[index].setTextListItem(item)
 }
 
 func (t *TextList) AppendItem(item TextListItem) {
@@ -222,13 +233,22 @@ func (t *TextList) ResetHeight() {
 	t.list.ResetHeight()
 }
 
+// maxItemWidth returns the width available for an item, and reports whether the width is limited.
+func (t *TextList) maxItemWidth(context *guigui.Context) (int, bool) {
+	if !t.list.widthSet {
+		return 0, false
+	}
+	return t.list.width - 2*(RoundedCornerRadius(context)+listItemPadding(context)), true
+}
+
 type textListItemWidget struct {
 	guigui.DefaultWidget
 
 	textList     *TextList
 	textListItem TextListItem
 
-	text Text
+	text    Text
+	tooltip Tooltip
 }
 
 /*func newTextListTextItem(settings *model.Settings, textList *TextList, textListItem TextListItem) *textListTextItem {
@@ -252,16 +272,47 @@ type textListItemWidget struct {
 }*/
 
 func (t *textListItemWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	t.text.SetText(t.textString())
+
+	// Truncate the text when the list is narrower than the text. The text's tooltip shows the entire text.
 	p := guigui.Position(t)
+	_, h := t.Size(context)
+	w, _ := t.text.TextSize(context)
+	maxW, ok := t.textList.maxItemWidth(context)
 	if t.textListItem.Header {
 		p.X += UnitSize(context) / 2
-		w, h := t.Size(context)
-		t.text.SetSize(w-UnitSize(context), h)
+		maxW -= UnitSize(context)
 	}
-	t.text.SetText(t.textString())
+	if ok {
+		w = min(w, max(maxW, 0))
+	}
+	t.text.SetTruncation(TextTruncationEnd)
+	t.text.SetSize(w, h)
 	t.text.SetVerticalAlign(VerticalAlignMiddle)
 	guigui.SetPosition(&t.text, p)
 	appender.AppendChildWidget(&t.text)
+
+	if t.textListItem.Tooltip != "" {
+		t.tooltip.SetText(t.textListItem.Tooltip)
+		guigui.SetPosition(&t.tooltip, guigui.Position(t))
+		appender.AppendChildWidget(&t.tooltip)
+	}
+}
+
+func (t *textListItemWidget) setTextListItem(item TextListItem) {
+	old := t.textListItem
+	t.textListItem = item
+
+	// Tag doesn't affect the appearance, and might not be comparable.
+	old.Tag = nil
+	item.Tag = nil
+	clr0, clr1 := old.Color, item.Color
+	old.Color = nil
+	item.Color = nil
+	if old == item && equalColor(clr0, clr1) {
+		return
+	}
+	guigui.RequestRelayout(t)
 }
 
 func (t *textListItemWidget) textString() string {
@@ -301,14 +352,52 @@ func (t *textListItemWidget) Size(context *guigui.Context) (int, int) {
 	return w, int(LineHeight(context))
 }
 
-/*func (t *textListItemWidget) index() int {
+func (t *textListItemWidget) index() int {
 	for i, tt := range t.textList.textListItemWidgets {
 		if tt == t {
 			return i
 		}
 	}
 	return -1
-}*/
+}
+
+func (t *textListItemWidget) Accessibility(context *guigui.Context) guigui.Accessibility {
+	if t.textListItem.Border {
+		return guigui.Accessibility{
+			Role: guigui.AccessibilityRoleSeparator,
+			Leaf: true,
+		}
+	}
+	if t.textListItem.Header {
+		return guigui.Accessibility{
+			Role:  guigui.AccessibilityRoleText,
+			Label: t.textString(),
+			Leaf:  true,
+		}
+	}
+	a := guigui.Accessibility{
+		Role:     guigui.AccessibilityRoleListItem,
+		Label:    t.textString(),
+		Selected: t.textList.SelectedItemIndex() == t.index(),
+		Disabled: !t.selectable(),
+		Leaf:     true,
+	}
+	if t.textList.list.style == ListStyleMenu {
+		a.Role = guigui.AccessibilityRoleMenuItem
+	}
+	if t.selectable() {
+		a.Actions = []guigui.AccessibilityAction{guigui.AccessibilityActionSelect}
+	}
+	return a
+}
+
+func (t *textListItemWidget) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionSelect || !t.selectable() {
+		return false
+	}
+	t.textList.SetSelectedItemIndex(t.index())
+	return true
+}
 
 func (t *textListItemWidget) selectable() bool {
 	return t.textListItem.selectable() && !t.textListItem.Border
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"slices"
+	"strings"
+)
+
+const textMaskString = "•"
+
+// textMask is a masked text and the mapping of the indices between the original text and the masked text.
+type textMask struct {
+	text   string
+	masked string
+
+	// offsets and maskedOffsets are the boundaries of the grapheme clusters in the original and the masked texts.
+	offsets       []int
+	maskedOffsets []int
+}
+
+// get returns the mask for the text. The last result is cached.
+func (t *textMask) get(text string) *textMask {
+	if t.offsets != nil && t.text == text {
+		return t
+	}
+
+	t.text = text
+	t.offsets = graphemeOffsets(text)
+	t.maskedOffsets = slices.Delete(t.maskedOffsets, 0, len(t.maskedOffsets))
+
+	var sb strings.Builder
+	t.maskedOffsets = append(t.maskedOffsets, 0)
+	for i := 0; i < len(t.offsets)-1; i++ {
+		// Keep line breaks so that the lines are kept.
+		if c := text[t.offsets[i]:t.offsets[i+1]]; c == "\n" || c == "\r\n" {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(textMaskString)
+		}
+		t.maskedOffsets = append(t.maskedOffsets, sb.Len())
+	}
+	t.masked = sb.String()
+	return t
+}
+
+// maskedIndex converts an index in the original text to an index in the masked text.
+func (t *textMask) maskedIndex(index int) int {
+	i, _ := slices.BinarySearch(t.offsets, index)
+	if i >= len(t.offsets) || t.offsets[i] != index {
+		// The index is in the middle of a cluster. Use the start of the cluster.
+		i--
+	}
+	return t.maskedOffsets[max(i, 0)]
+}
+
+// index converts an index in the masked text to an index in the original text.
+func (t *textMask) index(maskedIndex int) int {
+	i, _ := slices.BinarySearch(t.maskedOffsets, maskedIndex)
+	if i >= len(t.maskedOffsets) || t.maskedOffsets[i] != maskedIndex {
+		i--
+	}
+	return t.offsets[max(i, 0)]
+}
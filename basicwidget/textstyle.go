@@ -0,0 +1,500 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/xackery/guigui"
+)
+
+// TextStyle is a style of a part of a text.
+type TextStyle struct {
+	// Color is the color of the text. If Color is nil, the color of Text is used.
+	Color color.Color
+
+	// Weight is the weight of the text. If Weight is 0, the weight of Text is used.
+	Weight text.Weight
+
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+
+	// BackgroundColor is the color to highlight the text. If BackgroundColor is nil, the text is not highlighted.
+	BackgroundColor color.Color
+
+	// Link is the target of the link. If Link is not empty, the text is rendered as a link,
+	// and clicking it calls the function set by SetOnLinkClicked.
+	Link string
+}
+
+// TextRun is a part of a styled text.
+type TextRun struct {
+	Text  string
+	Style TextStyle
+}
+
+type textSpan struct {
+	start int
+	end   int
+	style TextStyle
+}
+
+// italicSkew is the skew angle of a synthesized italic in radians.
+const italicSkew = -0.2
+
+type styledSegment struct {
+	start int
+	end   int
+	style TextStyle
+	face  text.Face
+	x     float64
+	width float64
+}
+
+type styledLine struct {
+	start    int
+	end      int
+	width    float64
+	segments []styledSegment
+}
+
+type styledLinesCacheKey struct {
+	text string
+	face text.Face
+}
+
+// SetStyledText sets the text consisting of the runs. Adjacent runs with the same style are merged.
+//
+// The styles follow the edits of the text. A text inserted at a boundary of runs takes the style of the preceding character.
+// The styles are removed by SetText, and are not rendered while the text is masked or being composed by an IME.
+func (t *Text) SetStyledText(runs []TextRun) {
+	var sb strings.Builder
+	var spans []textSpan
+	for _, r := range runs {
+		if r.Text == "" {
+			continue
+		}
+		start := sb.Len()
+		sb.WriteString(r.Text)
+		spans = append(spans, textSpan{
+			start: start,
+			end:   sb.Len(),
+			style: r.Style,
+		})
+	}
+	str := sb.String()
+	t.SetText(str)
+	t.spans = mergeTextSpans(spans)
+	t.styledText = str
+	t.styledLinesCache = nil
+	t.wrapCache = textWrap{}
+	guigui.RequestRedraw(t)
+	t.resetCachedSize()
+}
+
+// equalTextStyle reports whether the two styles are the same.
+func equalTextStyle(a, b TextStyle) bool {
+	return equalColor(a.Color, b.Color) &&
+		a.Weight == b.Weight &&
+		a.Italic == b.Italic &&
+		a.Underline == b.Underline &&
+		a.Strikethrough == b.Strikethrough &&
+		equalColor(a.BackgroundColor, b.BackgroundColor) &&
+		a.Link == b.Link
+}
+
+// mergeTextSpans removes the empty spans and merges the adjacent spans with the same style.
+func mergeTextSpans(spans []textSpan) []textSpan {
+	merged := spans[:0]
+	for _, s := range spans {
+		if s.start >= s.end {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].end == s.start && equalTextStyle(merged[n-1].style, s.style) {
+			merged[n-1].end = s.end
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// adjustSpans moves the spans by the edit from the state before to the state after.
+func (t *Text) adjustSpans(before, after textEditState) {
+	if len(t.spans) == 0 || before.text == after.text || before.text != t.styledText {
+		return
+	}
+
+	start, oldEnd, newEnd := editedRange(before, after)
+	shift := newEnd - oldEnd
+	adjust := func(index int, isStart bool) int {
+		switch {
+		case index < start:
+			return index
+		case isStart && index == 0:
+			// A text inserted at the beginning takes the style of the following character.
+			return 0
+		case index >= oldEnd:
+			return index + shift
+		default:
+			// The replaced range belongs to the span of the preceding character.
+			return newEnd
+		}
+	}
+	for i := range t.spans {
+		t.spans[i].start = adjust(t.spans[i].start, true)
+		t.spans[i].end = adjust(t.spans[i].end, false)
+	}
+	t.spans = mergeTextSpans(t.spans)
+	t.styledText = after.text
+	t.styledLinesCache = nil
+	t.wrapCache = textWrap{}
+}
+
+// editedRange returns the range [start, oldEnd) of the text before the edit, which is replaced with the range [start, newEnd) of the text after the edit.
+//
+// The selections are used to locate the edit when the texts alone are ambiguous,
+// e.g. when a character is inserted next to the same character.
+func editedRange(before, after textEditState) (start, oldEnd, newEnd int) {
+	oldText, newText := before.text, after.text
+
+	limit := min(len(oldText), len(newText), before.start, after.start)
+	for start < limit && oldText[start] == newText[start] {
+		start++
+	}
+	for start > 0 && start < len(oldText) && !utf8.RuneStart(oldText[start]) {
+		start--
+	}
+
+	// The edited range of the text after the edit includes the selection after the edit, e.g. the caret after typing.
+	limit = min(len(oldText)-start, len(newText)-max(start, after.end))
+	var n int
+	for n < limit && oldText[len(oldText)-1-n] == newText[len(newText)-1-n] {
+		n++
+	}
+	for n > 0 && !utf8.RuneStart(oldText[len(oldText)-n]) {
+		n--
+	}
+	return start, len(oldText) - n, len(newText) - n
+}
+
+// SetOnLinkClicked sets the function called when a link in the styled text is clicked.
+// Links are not clickable while the text is editable.
+func (t *Text) SetOnLinkClicked(f func(link string)) {
+	t.onLinkClicked = f
+}
+
+func (t *Text) isStyled() bool {
+	return len(t.spans) > 0 && !t.masked && t.field.TextForRendering() == t.styledText
+}
+
+func (t *Text) faceWithWeight(context *guigui.Context, weight text.Weight) text.Face {
+	if weight == 0 {
+		return t.face(context)
+	}
+	return t.doFace(context, weight)
+}
+
+// styledAdvance returns the width of the range of the styled text str.
+func (t *Text) styledAdvance(context *guigui.Context, str string, start, end int) float64 {
+	face := t.face(context)
+	var w float64
+	pos := start
+	for _, s := range t.spans {
+		s0 := max(s.start, pos)
+		s1 := min(s.end, end)
+		if s0 >= s1 {
+			continue
+		}
+		w += text.Advance(str[pos:s0], face)
+		w += text.Advance(str[s0:s1], t.faceWithWeight(context, s.style.Weight))
+		pos = s1
+	}
+	w += text.Advance(str[pos:end], face)
+	return w
+}
+
+// styledLines returns the layout of the styled text str. str might be wrapped.
+func (t *Text) styledLines(context *guigui.Context, str string) []styledLine {
+	face := t.face(context)
+	key := styledLinesCacheKey{
+		text: str,
+		face: face,
+	}
+	if t.styledLinesCache != nil && t.styledLinesCacheKey == key {
+		return t.styledLinesCache
+	}
+
+	// Convert the spans into the indices in the truncated or wrapped text.
+	spans := t.spans
+	if tr := t.truncate(context); tr != nil {
+		spans = make([]textSpan, len(t.spans))
+		for i, s := range t.spans {
+			spans[i] = textSpan{
+				start: tr.truncatedIndex(s.start),
+				end:   tr.truncatedIndex(s.end),
+				style: s.style,
+			}
+		}
+	}
+	if w := t.wrap(context); w != nil {
+		spans = make([]textSpan, len(t.spans))
+		for i, s := range t.spans {
+			spans[i] = textSpan{
+				start: w.wrappedIndex(s.start),
+				end:   w.wrappedEndIndex(s.end),
+				style: s.style,
+			}
+		}
+	}
+
+	var lines []styledLine
+	var lineStart int
+	for {
+		lineEnd := len(str)
+		if i := strings.IndexByte(str[lineStart:], '\n'); i >= 0 {
+			lineEnd = lineStart + i
+		}
+
+		line := styledLine{
+			start: lineStart,
+			end:   lineEnd,
+		}
+		appendSegment := func(start, end int, style TextStyle) {
+			if start >= end {
+				return
+			}
+			f := t.faceWithWeight(context, style.Weight)
+			w := text.Advance(str[start:end], f)
+			line.segments = append(line.segments, styledSegment{
+				start: start,
+				end:   end,
+				style: style,
+				face:  f,
+				x:     line.width,
+				width: w,
+			})
+			line.width += w
+		}
+		pos := lineStart
+		for _, s := range spans {
+			start := max(s.start, lineStart)
+			end := min(s.end, lineEnd)
+			if start >= end {
+				continue
+			}
+			appendSegment(pos, start, TextStyle{})
+			appendSegment(start, end, s.style)
+			pos = end
+		}
+		appendSegment(pos, lineEnd, TextStyle{})
+		lines = append(lines, line)
+
+		if lineEnd == len(str) {
+			break
+		}
+		lineStart = lineEnd + 1
+	}
+
+	t.styledLinesCacheKey = key
+	t.styledLinesCache = lines
+	return lines
+}
+
+func (t *Text) styledLineLeft(textBounds image.Rectangle, line styledLine) float64 {
+	x := float64(textBounds.Min.X)
+	switch t.hAlign {
+	case HorizontalAlignCenter:
+		x += (float64(textBounds.Dx()) - line.width) / 2
+	case HorizontalAlignEnd:
+		x += float64(textBounds.Dx()) - line.width
+	}
+	return x
+}
+
+func (t *Text) styledTextWidth(context *guigui.Context, str string) float64 {
+	var w float64
+	for _, l := range t.styledLines(context, str) {
+		w = max(w, l.width)
+	}
+	return w
+}
+
+func (t *Text) styledTextPosition(context *guigui.Context, textBounds image.Rectangle, str string, index int, face text.Face, lineHeight float64) (x, top, bottom float64, ok bool) {
+	if index < 0 || index > len(str) {
+		return 0, 0, 0, false
+	}
+
+	m := face.Metrics()
+	paddingY := (lineHeight - (m.HAscent + m.HDescent)) / 2
+
+	for i, l := range t.styledLines(context, str) {
+		if index > l.end {
+			continue
+		}
+		x := t.styledLineLeft(textBounds, l)
+		for _, s := range l.segments {
+			if index > s.end {
+				continue
+			}
+			x += s.x + text.Advance(str[s.start:index], s.face)
+			break
+		}
+		y := float64(textBounds.Min.Y) + float64(i)*lineHeight
+		return x, y + paddingY, y + lineHeight - paddingY, true
+	}
+	return 0, 0, 0, false
+}
+
+func (t *Text) styledTextIndexFromPosition(context *guigui.Context, textBounds image.Rectangle, position image.Point, str string, face text.Face, lineHeight float64) int {
+	lines := t.styledLines(context, str)
+
+	// Determine the line first.
+	m := face.Metrics()
+	gap := lineHeight - m.HAscent - m.HDescent
+	top := float64(textBounds.Min.Y)
+	n := int((float64(position.Y) - top + gap/2) / lineHeight)
+	n = min(max(n, 0), len(lines)-1)
+
+	line := lines[n]
+	left := t.styledLineLeft(textBounds, line)
+	for _, s := range line.segments {
+		if float64(position.X)-left >= s.x+s.width {
+			continue
+		}
+		segment := str[s.start:s.end]
+		var prevA float64
+		for _, c := range visibleCulsters(segment, s.face) {
+			a := text.Advance(segment[:c.EndIndexInBytes], s.face)
+			if (float64(position.X) - left - s.x) < (prevA + (a-prevA)/2) {
+				return s.start + c.StartIndexInBytes
+			}
+			prevA = a
+		}
+		return s.end
+	}
+	return line.end
+}
+
+// linkAt returns the link at the position.
+func (t *Text) linkAt(context *guigui.Context, position image.Point) (string, bool) {
+	if !t.isStyled() || !position.In(guigui.VisibleBounds(t)) {
+		return "", false
+	}
+	textBounds := t.textBounds(context)
+	lh := t.lineHeight(context)
+	for i, l := range t.styledLines(context, t.textToDraw(context)) {
+		y := float64(textBounds.Min.Y) + float64(i)*lh
+		if float64(position.Y) < y || float64(position.Y) >= y+lh {
+			continue
+		}
+		left := t.styledLineLeft(textBounds, l)
+		for _, s := range l.segments {
+			if s.style.Link == "" {
+				continue
+			}
+			if float64(position.X) >= left+s.x && float64(position.X) < left+s.x+s.width {
+				return s.style.Link, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (t *Text) handleLinkInput(context *guigui.Context) bool {
+	if t.editable || !t.isStyled() {
+		t.pressedLink = ""
+		return false
+	}
+	input := context.Input()
+	if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		link, ok := t.linkAt(context, input.CursorPosition())
+		if !ok {
+			return false
+		}
+		t.pressedLink = link
+		return !t.selectable
+	}
+	if t.pressedLink != "" && input.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		pressedLink := t.pressedLink
+		t.pressedLink = ""
+		link, ok := t.linkAt(context, input.CursorPosition())
+		if !ok || link != pressedLink {
+			return false
+		}
+		// Do not follow the link when a part of the text is selected by dragging.
+		if start, end := t.field.Selection(); start != end {
+			return false
+		}
+		if t.onLinkClicked != nil {
+			t.onLinkClicked(link)
+		}
+		return !t.selectable
+	}
+	return false
+}
+
+func (t *Text) drawStyledText(context *guigui.Context, dst *ebiten.Image, textBounds image.Rectangle, str string, face text.Face, drawBackground bool) {
+	if dst.Bounds() != textBounds {
+		dst = dst.SubImage(textBounds).(*ebiten.Image)
+	}
+
+	lh := t.lineHeight(context)
+	m := face.Metrics()
+	paddingY := (lh - (m.HAscent + m.HDescent)) / 2
+	defaultColor := t.textColor(context)
+	lineWidth := float32(math.Max(1, math.Round(context.Scale()*(t.scaleMinus1+1))))
+
+	for i, l := range t.styledLines(context, str) {
+		left := t.styledLineLeft(textBounds, l)
+		y := float64(textBounds.Min.Y) + float64(i)*lh
+		baseline := y + paddingY + m.HAscent
+
+		for _, s := range l.segments {
+			x := left + s.x
+			if drawBackground {
+				if s.style.BackgroundColor != nil {
+					vector.DrawFilledRect(dst, float32(x), float32(y+paddingY), float32(s.width), float32(lh-2*paddingY), t.applyOpacity(s.style.BackgroundColor), false)
+				}
+				continue
+			}
+
+			clr := defaultColor
+			if s.style.Color != nil {
+				clr = t.applyOpacity(s.style.Color)
+			} else if s.style.Link != "" {
+				clr = t.applyOpacity(Color(context.ColorMode(), ColorTypeAccent, 0.5))
+			}
+
+			sm := s.face.Metrics()
+			op := &text.DrawOptions{}
+			if s.style.Italic {
+				op.GeoM.Translate(0, -sm.HAscent)
+				op.GeoM.Skew(italicSkew, 0)
+				op.GeoM.Translate(0, sm.HAscent)
+			}
+			op.GeoM.Translate(x, baseline-sm.HAscent)
+			op.ColorScale.ScaleWithColor(clr)
+			text.Draw(dst, str[s.start:s.end], s.face, op)
+
+			if s.style.Underline || s.style.Link != "" {
+				uy := float32(baseline + sm.HDescent/2)
+				vector.StrokeLine(dst, float32(x), uy, float32(x+s.width), uy, lineWidth, clr, false)
+			}
+			if s.style.Strikethrough {
+				sy := float32(baseline - sm.HAscent*0.28)
+				vector.StrokeLine(dst, float32(x), sy, float32(x+s.width), sy, lineWidth, clr, false)
+			}
+		}
+	}
+}
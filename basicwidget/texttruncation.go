@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type TextTruncation int
+
+const (
+	TextTruncationNone TextTruncation = iota
+	TextTruncationEnd
+	TextTruncationMiddle
+	TextTruncationStart
+)
+
+const textEllipsis = "…"
+
+type textTruncationKey struct {
+	text       string
+	width      float64
+	face       text.Face
+	styled     bool
+	truncation TextTruncation
+}
+
+// textCut is a range of the original text replaced with the ellipsis.
+type textCut struct {
+	start int
+	end   int
+}
+
+// textTruncationCache is a text truncated by a width and the mapping of the indices between the original text and the truncated text.
+// Each line is truncated independently.
+type textTruncationCache struct {
+	key       textTruncationKey
+	truncated string
+
+	// cuts is the ranges replaced with the ellipsis in ascending order.
+	cuts []textCut
+
+	valid bool
+}
+
+// get returns the truncation for the key. The last result is cached.
+// advance returns the width of the range of the text.
+func (t *textTruncationCache) get(key textTruncationKey, advance func(start, end int) float64) *textTruncationCache {
+	if t.valid && t.key == key {
+		return t
+	}
+
+	t.key = key
+	t.cuts = slices.Delete(t.cuts, 0, len(t.cuts))
+
+	ew := text.Advance(textEllipsis, key.face)
+	var lineStart int
+	for {
+		lineEnd := len(key.text)
+		if i := strings.IndexByte(key.text[lineStart:], '\n'); i >= 0 {
+			lineEnd = lineStart + i
+		}
+		if c, ok := truncateLine(key.text, lineStart, lineEnd, key.width, ew, key.truncation, advance); ok {
+			t.cuts = append(t.cuts, c)
+		}
+		if lineEnd == len(key.text) {
+			break
+		}
+		lineStart = lineEnd + 1
+	}
+
+	var sb strings.Builder
+	var pos int
+	for _, c := range t.cuts {
+		sb.WriteString(key.text[pos:c.start])
+		sb.WriteString(textEllipsis)
+		pos = c.end
+	}
+	sb.WriteString(key.text[pos:])
+	t.truncated = sb.String()
+	t.valid = true
+	return t
+}
+
+func (t *textTruncationCache) isTruncated() bool {
+	return len(t.cuts) > 0
+}
+
+// truncatedIndex converts an index in the original text to an index in the truncated text.
+// An index in a cut range is converted to the start of the ellipsis.
+func (t *textTruncationCache) truncatedIndex(index int) int {
+	var shift int
+	for _, c := range t.cuts {
+		if index < c.start {
+			break
+		}
+		if index < c.end {
+			return c.start + shift
+		}
+		shift += len(textEllipsis) - (c.end - c.start)
+	}
+	return index + shift
+}
+
+// index converts an index in the truncated text to an index in the original text.
+// An index in the ellipsis is converted to the start of the cut range.
+func (t *textTruncationCache) index(truncatedIndex int) int {
+	var shift int
+	for _, c := range t.cuts {
+		if truncatedIndex < c.start+shift {
+			break
+		}
+		if truncatedIndex < c.start+shift+len(textEllipsis) {
+			return c.start
+		}
+		shift += len(textEllipsis) - (c.end - c.start)
+	}
+	return truncatedIndex - shift
+}
+
+// truncateLine returns the range of the line [start, end) to replace with the ellipsis to fit with the width.
+// The range is at grapheme cluster boundaries.
+func truncateLine(str string, start, end int, width, ellipsisWidth float64, truncation TextTruncation, advance func(start, end int) float64) (textCut, bool) {
+	if advance(start, end) <= width {
+		return textCut{}, false
+	}
+
+	offsets := graphemeOffsets(str[start:end])
+	for i := range offsets {
+		offsets[i] += start
+	}
+
+	switch truncation {
+	case TextTruncationEnd:
+		// Find the longest prefix that fits with the ellipsis.
+		n := sort.Search(len(offsets), func(i int) bool {
+			return advance(start, offsets[i])+ellipsisWidth > width
+		})
+		n = max(n-1, 0)
+		return textCut{start: offsets[n], end: end}, true
+	case TextTruncationStart:
+		// Find the longest suffix that fits with the ellipsis.
+		n := sort.Search(len(offsets), func(i int) bool {
+			return ellipsisWidth+advance(offsets[i], end) <= width
+		})
+		n = min(n, len(offsets)-1)
+		return textCut{start: start, end: offsets[n]}, true
+	case TextTruncationMiddle:
+		// Add the grapheme clusters from the both sides alternately.
+		available := width - ellipsisWidth
+		head, tail := 0, len(offsets)-1
+		for head < tail {
+			if head <= len(offsets)-1-tail {
+				if advance(start, offsets[head+1])+advance(offsets[tail], end) > available {
+					break
+				}
+				head++
+			} else {
+				if advance(start, offsets[head])+advance(offsets[tail-1], end) > available {
+					break
+				}
+				tail--
+			}
+		}
+		return textCut{start: offsets[head], end: offsets[tail]}, true
+	}
+	return textCut{}, false
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/xackery/guigui"
+)
+
+func TestTruncateLine(t *testing.T) {
+	const (
+		e      = "e\u0301"                                    // e with a combining acute accent: 2 runes
+		family = "\U0001F468\u200d\U0001F469\u200d\U0001F467" // a ZWJ sequence: 5 runes
+		flag   = "\U0001F1EF\U0001F1F5"                       // a regional indicator pair: 2 runes
+		str    = "a" + e + family + flag + "b"                // 11 runes, 5 grapheme clusters
+	)
+
+	testCases := []struct {
+		name       string
+		str        string
+		width      float64
+		truncation TextTruncation
+		want       string
+	}{
+		{name: "fit", str: str, width: 11, truncation: TextTruncationEnd, want: str},
+		{name: "end", str: str, width: 8, truncation: TextTruncationEnd, want: "a" + e + textEllipsis},
+		{name: "end combining", str: str, width: 3, truncation: TextTruncationEnd, want: "a" + textEllipsis},
+		{name: "end too narrow", str: str, width: 0, truncation: TextTruncationEnd, want: textEllipsis},
+		{name: "start", str: str, width: 9, truncation: TextTruncationStart, want: textEllipsis + family + flag + "b"},
+		{name: "start flag", str: str, width: 3, truncation: TextTruncationStart, want: textEllipsis + "b"},
+		{name: "start too narrow", str: str, width: 0, truncation: TextTruncationStart, want: textEllipsis},
+		{name: "middle", str: str, width: 9, truncation: TextTruncationMiddle, want: "a" + e + textEllipsis + flag + "b"},
+		{name: "middle narrow", str: str, width: 4, truncation: TextTruncationMiddle, want: "a" + textEllipsis + "b"},
+		{name: "middle combining", str: e + e + e + e, width: 6, truncation: TextTruncationMiddle, want: e + textEllipsis + e},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Every rune has the width 1, so a cut in the middle of a grapheme cluster would fit more runes.
+			advance := func(start, end int) float64 {
+				return float64(utf8.RuneCountInString(tc.str[start:end]))
+			}
+			got := tc.str
+			if c, ok := truncateLine(tc.str, 0, len(tc.str), tc.width, 1, tc.truncation, advance); ok {
+				got = tc.str[:c.start] + textEllipsis + tc.str[c.end:]
+			}
+			if got != tc.want {
+				t.Errorf("got: %q, want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTruncateLineInLine(t *testing.T) {
+	// A line after the first line is truncated within the line.
+	str := "abc\nd" + "é" + "fg"
+	start := len("abc\n")
+	advance := func(start, end int) float64 {
+		return float64(utf8.RuneCountInString(str[start:end]))
+	}
+	c, ok := truncateLine(str, start, len(str), 3, 1, TextTruncationEnd, advance)
+	if !ok {
+		t.Fatal("truncateLine: got: false, want: true")
+	}
+	if got, want := str[:c.start]+textEllipsis+str[c.end:], "abc\nd"+textEllipsis; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestTextListAndFormTruncation(t *testing.T) {
+	long := strings.Repeat("long text ", 20)
+
+	var textList TextList
+	textList.SetItemsByStrings([]string{"short", long})
+	textList.SetWidth(200)
+
+	var label Text
+	label.SetText(long)
+	var textField TextField
+	var form Form
+	form.SetItems([]*FormItem{
+		{PrimaryWidget: &label, SecondaryWidget: &textField},
+	})
+
+	d := newTestDriver(&textList, &form)
+	form.SetWidth(d.Context(), 400)
+	if err := d.StepN(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if textList.textListItemWidgets[0].text.IsTruncated(d.Context()) {
+		t.Errorf("the short item is truncated")
+	}
+	item := textList.textListItemWidgets[1]
+	if !item.text.IsTruncated(d.Context()) {
+		t.Errorf("the long item is not truncated")
+	}
+	if got, limit := guigui.Bounds(&item.text).Max.X, guigui.Bounds(&textList).Max.X; got > limit {
+		t.Errorf("the long item's right: got: %d, want: <= %d", got, limit)
+	}
+
+	if !label.IsTruncated(d.Context()) {
+		t.Errorf("the form label is not truncated")
+	}
+	if got, limit := guigui.Bounds(&label).Max.X, guigui.Bounds(&textField).Min.X; got > limit {
+		t.Errorf("the form label's right: got: %d, want: <= %d", got, limit)
+	}
+}
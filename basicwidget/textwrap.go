@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-text/typesetting/segmenter"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type textWrapKey struct {
+	text   string
+	width  float64
+	face   text.Face
+	styled bool
+}
+
+// textWrap is a text wrapped by a width and the mapping of the indices between the original text and the wrapped text.
+// The wrapped text has '\n' at each soft line break.
+type textWrap struct {
+	key     textWrapKey
+	wrapped string
+
+	// breaks is the indices of the soft line breaks in the original text in ascending order.
+	breaks []int
+
+	valid bool
+}
+
+// get returns the wrap for the key. The last result is cached.
+// advance returns the width of the range of the text.
+func (t *textWrap) get(key textWrapKey, advance func(start, end int) float64) *textWrap {
+	if t.valid && t.key == key {
+		return t
+	}
+
+	t.key = key
+	t.breaks = appendSoftLineBreaks(slices.Delete(t.breaks, 0, len(t.breaks)), key.text, key.width, advance)
+
+	var sb strings.Builder
+	var pos int
+	for _, b := range t.breaks {
+		sb.WriteString(key.text[pos:b])
+		sb.WriteString("\n")
+		pos = b
+	}
+	sb.WriteString(key.text[pos:])
+	t.wrapped = sb.String()
+	t.valid = true
+	return t
+}
+
+// wrappedIndex converts an index in the original text to an index in the wrapped text.
+// An index at a soft line break is converted to the start of the next line.
+func (t *textWrap) wrappedIndex(index int) int {
+	n, found := slices.BinarySearch(t.breaks, index)
+	if found {
+		n++
+	}
+	return index + n
+}
+
+// wrappedEndIndex is like wrappedIndex, but an index at a soft line break is converted to the end of the previous line.
+func (t *textWrap) wrappedEndIndex(index int) int {
+	n, _ := slices.BinarySearch(t.breaks, index)
+	return index + n
+}
+
+// index converts an index in the wrapped text to an index in the original text.
+func (t *textWrap) index(wrappedIndex int) int {
+	// The j-th inserted line break is at breaks[j] + j in the wrapped text.
+	n := sort.Search(len(t.breaks), func(j int) bool {
+		return t.breaks[j]+j >= wrappedIndex
+	})
+	return wrappedIndex - n
+}
+
+// isSoftLineBreak reports whether the index in the original text is at a soft line break.
+func (t *textWrap) isSoftLineBreak(index int) bool {
+	_, found := slices.BinarySearch(t.breaks, index)
+	return found
+}
+
+type lineBreakOpportunity struct {
+	offset    int
+	mandatory bool
+}
+
+// lineBreakOpportunities returns the positions where lines can break in bytes by the Unicode line breaking algorithm (UAX #14).
+//
+// The break classes of UAX #14 cover the Japanese line breaking rules (kinsoku) as well.
+// For example, a line doesn't start with closing brackets (CL, CP), sentence punctuations (EX, IS),
+// or small kana (CJ, resolved to NS).
+func lineBreakOpportunities(str string) []lineBreakOpportunity {
+	runes := make([]rune, 0, len(str))
+	offsets := make([]int, 0, len(str)+1)
+	for i, r := range str {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(str))
+
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	var result []lineBreakOpportunity
+	iter := seg.LineIterator()
+	for iter.Next() {
+		l := iter.Line()
+		result = append(result, lineBreakOpportunity{
+			offset:    offsets[l.Offset+len(l.Text)],
+			mandatory: l.IsMandatoryBreak,
+		})
+	}
+	return result
+}
+
+// trimTrailingSpaces returns the end of the range without the trailing spaces and line breaks.
+// Trailing spaces don't count for the line width.
+func trimTrailingSpaces(str string, start, end int) int {
+	return start + len(strings.TrimRightFunc(str[start:end], unicode.IsSpace))
+}
+
+// appendSoftLineBreaks appends the positions to break the lines of str in bytes so that each line fits with the width.
+// If a part between line break opportunities doesn't fit with the width, the part is broken at grapheme cluster boundaries.
+func appendSoftLineBreaks(breaks []int, str string, width float64, advance func(start, end int) float64) []int {
+	lineStart := 0
+	lastFit := -1
+	for _, o := range lineBreakOpportunities(str) {
+		for {
+			end := trimTrailingSpaces(str, lineStart, o.offset)
+			if advance(lineStart, end) <= width {
+				lastFit = o.offset
+				break
+			}
+			if lastFit > lineStart {
+				breaks = append(breaks, lastFit)
+				lineStart = lastFit
+				lastFit = -1
+				continue
+			}
+
+			// Even the first part doesn't fit. Break the part at the grapheme cluster boundaries.
+			offsets := graphemeOffsets(str[lineStart:end])
+			n := 1
+			for n+1 < len(offsets) && advance(lineStart, lineStart+offsets[n+1]) <= width {
+				n++
+			}
+			if lineStart+offsets[n] >= end {
+				lastFit = o.offset
+				break
+			}
+			breaks = append(breaks, lineStart+offsets[n])
+			lineStart += offsets[n]
+			lastFit = -1
+		}
+		if o.mandatory {
+			lineStart = o.offset
+			lastFit = -1
+		}
+	}
+	return breaks
+}
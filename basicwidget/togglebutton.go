@@ -16,6 +16,8 @@ type ToggleButton struct {
 	guigui.DefaultWidget
 
 	mouseOverlay guigui.MouseOverlay
+	tooltip      Tooltip
+	focusRing    focusRing
 
 	value        bool
 	onceRendered bool
@@ -29,6 +31,11 @@ func (t *ToggleButton) SetOnValueChanged(f func(value bool)) {
 	t.onValueChanged = f
 }
 
+// SetTooltip sets the text shown when the cursor stays on the toggle button.
+func (t *ToggleButton) SetTooltip(text string) {
+	t.tooltip.SetText(text)
+}
+
 func (t *ToggleButton) Value() bool {
 	return t.value
 }
@@ -63,6 +70,22 @@ func (t *ToggleButton) Layout(context *guigui.Context, appender *guigui.ChildWid
 
 	guigui.SetPosition(&t.mouseOverlay, guigui.Position(t))
 	appender.AppendChildWidget(&t.mouseOverlay)
+
+	if t.tooltip.HasContent() {
+		guigui.SetPosition(&t.tooltip, guigui.Position(t))
+		appender.AppendChildWidget(&t.tooltip)
+	}
+
+	guigui.SetFocusable(t, true)
+	appendFocusRing(context, appender, &t.focusRing, t)
+}
+
+func (t *ToggleButton) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if guigui.IsEnabled(t) && guigui.HasFocusedChildWidget(t) && isActivationKeyJustPressed(context.Input()) {
+		t.SetValue(!t.value)
+		return guigui.HandleInputByWidget(t)
+	}
+	return guigui.HandleInputResult{}
 }
 
 func (t *ToggleButton) Update(context *guigui.Context) error {
@@ -138,6 +161,23 @@ func (t *ToggleButton) Draw(context *guigui.Context, dst *ebiten.Image) {
 	t.onceRendered = true
 }
 
+func (t *ToggleButton) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return guigui.Accessibility{
+		Role:    guigui.AccessibilityRoleSwitch,
+		Checked: t.value,
+		Actions: []guigui.AccessibilityAction{guigui.AccessibilityActionPress},
+		Leaf:    true,
+	}
+}
+
+func (t *ToggleButton) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress {
+		return false
+	}
+	t.SetValue(!t.value)
+	return true
+}
+
 func (t *ToggleButton) isActive() bool {
 	return guigui.IsEnabled(t) && t.mouseOverlay.IsHovering() && t.mouseOverlay.IsPressing()
 }
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+func tooltipDelay() int {
+	return ebiten.TPS() / 2
+}
+
+func tooltipPadding(context *guigui.Context) image.Point {
+	return image.Pt(UnitSize(context)/4, UnitSize(context)/8)
+}
+
+// tooltipCursorOffset returns the offset of a tooltip from the cursor not to overlap with the cursor image.
+func tooltipCursorOffset(context *guigui.Context) int {
+	return int(16 * context.Scale())
+}
+
+type tooltipStateKey struct{}
+
+// tooltipState is the state of tooltips in an app.
+type tooltipState struct {
+	// active is the tooltip currently shown. Only one tooltip is shown at a time.
+	active *Tooltip
+}
+
+func tooltipStateFromContext(context *guigui.Context) *tooltipState {
+	if s, ok := context.Value(tooltipStateKey{}).(*tooltipState); ok {
+		return s
+	}
+	s := &tooltipState{}
+	context.SetValue(tooltipStateKey{}, s)
+	return s
+}
+
+func (s *tooltipState) activate(tooltip *Tooltip) {
+	if s.active == tooltip {
+		return
+	}
+	if s.active != nil {
+		guigui.RequestRelayout(s.active)
+	}
+	s.active = tooltip
+	guigui.RequestRelayout(tooltip)
+}
+
+func (s *tooltipState) deactivate(tooltip *Tooltip) {
+	if s.active != tooltip {
+		return
+	}
+	s.active = nil
+	guigui.RequestRelayout(tooltip)
+}
+
+// Tooltip shows a text or a widget in a popup when the cursor stays on the parent widget for a while.
+//
+// To attach a tooltip to a widget, append a Tooltip as a child widget in the widget's Layout.
+// The tooltip is hidden when a mouse button is pressed, the wheel is scrolled, or the focus is changed.
+// The tooltip is shown again after the cursor leaves the parent widget and comes back.
+type Tooltip struct {
+	guigui.DefaultWidget
+
+	popup tooltipPopup
+
+	text    string
+	content guigui.Widget
+
+	hoveringCount  int
+	dismissed      bool
+	cursorPosition image.Point
+	focusedWidget  guigui.Widget
+}
+
+// SetText sets the text to show.
+func (t *Tooltip) SetText(text string) {
+	if t.text == text {
+		return
+	}
+	t.text = text
+	guigui.RequestRelayout(t)
+}
+
+// SetContent sets the widget to show instead of the text.
+// If content is nil, the text is shown.
+func (t *Tooltip) SetContent(content guigui.Widget) {
+	if t.content == content {
+		return
+	}
+	t.content = content
+	guigui.RequestRelayout(t)
+}
+
+// HasContent reports whether the tooltip has a text or a widget to show.
+func (t *Tooltip) HasContent() bool {
+	return t.text != "" || t.content != nil
+}
+
+func (t *Tooltip) isShown(context *guigui.Context) bool {
+	return tooltipStateFromContext(context).active == t && t.hoveringCount >= tooltipDelay() && !t.dismissed && t.HasContent()
+}
+
+func (t *Tooltip) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	if !t.isShown(context) {
+		return
+	}
+
+	if t.content != nil {
+		t.popup.setContent(t.content)
+	} else {
+		t.popup.setText(t.text)
+	}
+
+	// Put the tooltip below the cursor. If the tooltip doesn't fit with the app, put it above the cursor.
+	appW, appH := context.AppSize()
+	w, h := t.popup.Size(context)
+	offset := tooltipCursorOffset(context)
+	p := t.cursorPosition.Add(image.Pt(0, offset))
+	if p.Y+h > appH {
+		p.Y = t.cursorPosition.Y - offset/2 - h
+	}
+	p.X = min(p.X, appW-w)
+	p.X = max(p.X, 0)
+	p.Y = max(p.Y, 0)
+	guigui.SetPosition(&t.popup, p)
+	appender.AppendChildWidget(&t.popup)
+}
+
+func (t *Tooltip) Update(context *guigui.Context) error {
+	input := context.Input()
+	state := tooltipStateFromContext(context)
+	parent := guigui.Parent(t)
+	if parent == nil || !guigui.IsVisible(t) || !input.CursorPosition().In(guigui.VisibleBounds(parent)) {
+		t.hoveringCount = 0
+		t.dismissed = false
+		state.deactivate(t)
+		return nil
+	}
+
+	// Hide the tooltip on a press, a scroll or a focus change.
+	if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
+		input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) ||
+		input.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
+		t.dismissed = true
+	}
+	if dx, dy := input.Wheel(); dx != 0 || dy != 0 {
+		t.dismissed = true
+	}
+	if t.hoveringCount > 0 && context.FocusedWidget() != t.focusedWidget {
+		t.dismissed = true
+	}
+	t.focusedWidget = context.FocusedWidget()
+
+	if t.dismissed {
+		state.deactivate(t)
+		return nil
+	}
+
+	t.hoveringCount++
+	if t.hoveringCount == tooltipDelay() {
+		t.cursorPosition = input.CursorPosition()
+		state.activate(t)
+	}
+	return nil
+}
+
+func (t *Tooltip) Size(context *guigui.Context) (int, int) {
+	return 0, 0
+}
+
+// tooltipPopup is the popup of a tooltip.
+type tooltipPopup struct {
+	guigui.DefaultWidget
+
+	text    *Text
+	content guigui.Widget
+}
+
+func (t *tooltipPopup) setText(text string) {
+	if t.text == nil {
+		t.text = &Text{}
+	}
+	t.text.SetText(text)
+	t.content = nil
+}
+
+func (t *tooltipPopup) setContent(content guigui.Widget) {
+	t.content = content
+}
+
+func (t *tooltipPopup) contentWidget() guigui.Widget {
+	if t.content != nil {
+		return t.content
+	}
+	if t.text != nil {
+		return t.text
+	}
+	return nil
+}
+
+func (t *tooltipPopup) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	if t.text != nil {
+		t.text.SetMultiline(true)
+	}
+	content := t.contentWidget()
+	if content == nil {
+		return
+	}
+	guigui.SetPosition(content, guigui.Position(t).Add(tooltipPadding(context)))
+	appender.AppendChildWidget(content)
+}
+
+func (t *tooltipPopup) IsPopup() bool {
+	return true
+}
+
+func (t *tooltipPopup) Draw(context *guigui.Context, dst *ebiten.Image) {
+	bounds := guigui.Bounds(t)
+	DrawRoundedRect(context, dst, bounds, Color(context.ColorMode(), ColorTypeBase, 0.95), RoundedCornerRadius(context))
+	DrawRoundedRectBorder(context, dst, bounds, Color(context.ColorMode(), ColorTypeBase, 0.7), RoundedCornerRadius(context), float32(1*context.Scale()), RoundedRectBorderTypeRegular)
+}
+
+func (t *tooltipPopup) Size(context *guigui.Context) (int, int) {
+	content := t.contentWidget()
+	if content == nil {
+		return 0, 0
+	}
+	w, h := content.Size(context)
+	p := tooltipPadding(context)
+	return w + 2*p.X, h + 2*p.Y
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package basicwidget
+
+import (
+	"testing"
+
+	"github.com/xackery/guigui"
+)
+
+type tooltipHost struct {
+	guigui.DefaultWidget
+
+	tooltip Tooltip
+}
+
+func (t *tooltipHost) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	t.tooltip.SetText("Tooltip")
+	guigui.SetPosition(&t.tooltip, guigui.Position(t))
+	appender.AppendChildWidget(&t.tooltip)
+}
+
+func (t *tooltipHost) Size(context *guigui.Context) (int, int) {
+	return 100, 20
+}
+
+func TestTooltipPerApp(t *testing.T) {
+	var host0, host1 tooltipHost
+	d0 := newTestDriver(&host0)
+	d1 := newTestDriver(&host1)
+
+	// Tooltips in different apps don't hide each other.
+	d0.MoveCursor(10, 10)
+	d1.MoveCursor(10, 10)
+	for range tooltipDelay() + 1 {
+		if err := d0.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := d1.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !host0.tooltip.isShown(d0.Context()) {
+		t.Errorf("the tooltip in the first app is not shown")
+	}
+	if !host1.tooltip.isShown(d1.Context()) {
+		t.Errorf("the tooltip in the second app is not shown")
+	}
+
+	// A scroll hides only the tooltip in the same app.
+	d0.Scroll(0, 1)
+	if err := d0.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d1.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if host0.tooltip.isShown(d0.Context()) {
+		t.Errorf("the tooltip in the first app is shown after a scroll")
+	}
+	if !host1.tooltip.isShown(d1.Context()) {
+		t.Errorf("the tooltip in the second app is not shown after a scroll in the first app")
+	}
+}
@@ -11,6 +11,7 @@ type ChildWidgetAppender struct {
 func (c *ChildWidgetAppender) AppendChildWidget(widget Widget) {
 	widgetState := widget.widgetState()
 	widgetState.parent = c.widget
+	widgetState.app = c.app
 	cWidgetState := c.widget.widgetState()
 	cWidgetState.children = append(cWidgetState.children, widget)
 }
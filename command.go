@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Modifiers int
+
+const (
+	ModifierShift Modifiers = 1 << iota
+	ModifierControl
+	ModifierAlt
+	ModifierMeta
+
+	// ModifierPrimary is the primary modifier of the platform: Meta (Command) on macOS and Control on the others.
+	ModifierPrimary
+)
+
+// KeyChord is a key with modifiers, like Ctrl+S.
+type KeyChord struct {
+	Key       ebiten.Key
+	Modifiers Modifiers
+}
+
+func isDarwin() bool {
+	// TODO: Detect macOS on browsers.
+	return runtime.GOOS == "darwin"
+}
+
+// resolve returns the modifiers with ModifierPrimary replaced with the platform's modifier.
+func (m Modifiers) resolve() Modifiers {
+	if m&ModifierPrimary == 0 {
+		return m
+	}
+	m &^= ModifierPrimary
+	if isDarwin() {
+		m |= ModifierMeta
+	} else {
+		m |= ModifierControl
+	}
+	return m
+}
+
+// resolve returns the chord with ModifierPrimary replaced with the platform's modifier.
+func (k KeyChord) resolve() KeyChord {
+	k.Modifiers = k.Modifiers.resolve()
+	return k
+}
+
+// String returns the chord in the platform's notation, like "Ctrl+Shift+S" or "⇧⌘S".
+func (k KeyChord) String() string {
+	k = k.resolve()
+	key := strings.TrimPrefix(k.Key.String(), "Digit")
+
+	if isDarwin() {
+		var str string
+		if k.Modifiers&ModifierControl != 0 {
+			str += "⌃"
+		}
+		if k.Modifiers&ModifierAlt != 0 {
+			str += "⌥"
+		}
+		if k.Modifiers&ModifierShift != 0 {
+			str += "⇧"
+		}
+		if k.Modifiers&ModifierMeta != 0 {
+			str += "⌘"
+		}
+		return str + key
+	}
+
+	var strs []string
+	if k.Modifiers&ModifierControl != 0 {
+		strs = append(strs, "Ctrl")
+	}
+	if k.Modifiers&ModifierAlt != 0 {
+		strs = append(strs, "Alt")
+	}
+	if k.Modifiers&ModifierShift != 0 {
+		strs = append(strs, "Shift")
+	}
+	if k.Modifiers&ModifierMeta != 0 {
+		strs = append(strs, "Win")
+	}
+	strs = append(strs, key)
+	return strings.Join(strs, "+")
+}
+
+// IsModifierPressed reports whether all the given modifiers are pressed. Other modifiers might also be pressed.
+// ModifierPrimary is treated as the platform's primary modifier.
+func (i *InputState) IsModifierPressed(modifiers Modifiers) bool {
+	modifiers = modifiers.resolve()
+	if modifiers&ModifierShift != 0 && !i.IsKeyPressed(ebiten.KeyShift) {
+		return false
+	}
+	if modifiers&ModifierControl != 0 && !i.IsKeyPressed(ebiten.KeyControl) {
+		return false
+	}
+	if modifiers&ModifierAlt != 0 && !i.IsKeyPressed(ebiten.KeyAlt) {
+		return false
+	}
+	if modifiers&ModifierMeta != 0 && !i.IsKeyPressed(ebiten.KeyMeta) {
+		return false
+	}
+	return true
+}
+
+func (k KeyChord) isJustPressed(input *InputState) bool {
+	if !input.IsKeyJustPressed(k.Key) {
+		return false
+	}
+	k = k.resolve()
+	return input.IsKeyPressed(ebiten.KeyShift) == (k.Modifiers&ModifierShift != 0) &&
+		input.IsKeyPressed(ebiten.KeyControl) == (k.Modifiers&ModifierControl != 0) &&
+		input.IsKeyPressed(ebiten.KeyAlt) == (k.Modifiers&ModifierAlt != 0) &&
+		input.IsKeyPressed(ebiten.KeyMeta) == (k.Modifiers&ModifierMeta != 0)
+}
+
+// Command is a named action of an app.
+type Command struct {
+	// Name is the unique name of the command.
+	Name string
+
+	// Title is the human-readable title of the command, e.g. for a help screen or a menu.
+	Title string
+
+	// Action is called when the command is executed.
+	Action func()
+
+	// Enabled reports whether the command can be executed. If Enabled is nil, the command is always enabled.
+	Enabled func() bool
+}
+
+func (c *Command) isEnabled() bool {
+	return c.Enabled == nil || c.Enabled()
+}
+
+// KeyBinding is a binding of a key chord to a command.
+type KeyBinding struct {
+	Chord   KeyChord
+	Command string
+}
+
+type commandRegistry struct {
+	commands []Command
+	bindings []KeyBinding
+}
+
+func (c *commandRegistry) command(name string) *Command {
+	idx := slices.IndexFunc(c.commands, func(command Command) bool {
+		return command.Name == name
+	})
+	if idx < 0 {
+		return nil
+	}
+	return &c.commands[idx]
+}
+
+// RegisterCommand registers the command.
+// RegisterCommand returns an error if a command with the same name is already registered.
+func (c *Context) RegisterCommand(command Command) error {
+	r := &c.app.commandRegistry
+	if r.command(command.Name) != nil {
+		return fmt.Errorf("guigui: command %q is already registered", command.Name)
+	}
+	r.commands = append(r.commands, command)
+	return nil
+}
+
+// UnregisterCommand unregisters the command and its key bindings.
+func (c *Context) UnregisterCommand(name string) {
+	r := &c.app.commandRegistry
+	r.commands = slices.DeleteFunc(r.commands, func(command Command) bool {
+		return command.Name == name
+	})
+	r.bindings = slices.DeleteFunc(r.bindings, func(binding KeyBinding) bool {
+		return binding.Command == name
+	})
+}
+
+// BindKey binds the key chord to the command.
+//
+// BindKey returns an error if the command is not registered,
+// or if the chord conflicts with a chord bound to another command on the current platform.
+func (c *Context) BindKey(chord KeyChord, name string) error {
+	r := &c.app.commandRegistry
+	if r.command(name) == nil {
+		return fmt.Errorf("guigui: command %q is not registered", name)
+	}
+	for _, b := range r.bindings {
+		if b.Chord.resolve() != chord.resolve() {
+			continue
+		}
+		if b.Command == name {
+			return nil
+		}
+		return fmt.Errorf("guigui: key chord %s for command %q conflicts with command %q", chord, name, b.Command)
+	}
+	r.bindings = append(r.bindings, KeyBinding{
+		Chord:   chord,
+		Command: name,
+	})
+	return nil
+}
+
+// UnbindKey removes the binding of the key chord.
+func (c *Context) UnbindKey(chord KeyChord) {
+	r := &c.app.commandRegistry
+	r.bindings = slices.DeleteFunc(r.bindings, func(binding KeyBinding) bool {
+		return binding.Chord.resolve() == chord.resolve()
+	})
+}
+
+// AppendKeyBindings appends all the key bindings in the registration order to bindings.
+func (c *Context) AppendKeyBindings(bindings []KeyBinding) []KeyBinding {
+	return append(bindings, c.app.commandRegistry.bindings...)
+}
+
+// AppendKeyChordsForCommand appends the key chords bound to the command to chords.
+func (c *Context) AppendKeyChordsForCommand(chords []KeyChord, name string) []KeyChord {
+	for _, b := range c.app.commandRegistry.bindings {
+		if b.Command == name {
+			chords = append(chords, b.Chord)
+		}
+	}
+	return chords
+}
+
+// Command returns the registered command with the given name.
+func (c *Context) Command(name string) (Command, bool) {
+	command := c.app.commandRegistry.command(name)
+	if command == nil {
+		return Command{}, false
+	}
+	return *command, true
+}
+
+// IsCommandEnabled reports whether the command is registered and enabled.
+func (c *Context) IsCommandEnabled(name string) bool {
+	command := c.app.commandRegistry.command(name)
+	return command != nil && command.isEnabled()
+}
+
+// ExecuteCommand executes the command and reports whether the command is executed.
+// A disabled command is not executed.
+func (c *Context) ExecuteCommand(name string) bool {
+	command := c.app.commandRegistry.command(name)
+	if command == nil || !command.isEnabled() {
+		return false
+	}
+	if command.Action != nil {
+		command.Action()
+	}
+	return true
+}
+
+// isHandledByFocusedWidget reports whether the inputs are handled by the focused widget or its ancestors.
+func (a *app) isHandledByFocusedWidget(r HandleInputResult) bool {
+	if r.widget == nil || a.focusedWidget == nil {
+		return false
+	}
+	return isAncestorOrSelf(r.widget, a.focusedWidget)
+}
+
+func (a *app) handleCommands() bool {
+	for _, b := range a.commandRegistry.bindings {
+		if !b.Chord.isJustPressed(&a.inputState) {
+			continue
+		}
+		if a.context.ExecuteCommand(b.Command) {
+			return true
+		}
+	}
+	return false
+}
@@ -69,6 +69,8 @@ type Context struct {
 	colorMode      ColorMode
 	hasColorMode   bool
 	locales        []language.Tag
+
+	values map[any]any
 }
 
 func (c *Context) Scale() float64 {
@@ -85,6 +87,7 @@ func (c *Context) setDeviceScale(deviceScale float64) {
 	}
 	c.deviceScale = deviceScale
 	c.app.requestRedraw(c.app.bounds())
+	c.app.requestFullRelayout()
 }
 
 func (c *Context) AppScale() float64 {
@@ -97,6 +100,7 @@ func (c *Context) SetAppScale(scale float64) {
 	}
 	c.appScaleMinus1 = scale - 1
 	c.app.requestRedraw(c.app.bounds())
+	c.app.requestFullRelayout()
 }
 
 func (c *Context) ColorMode() ColorMode {
@@ -114,10 +118,16 @@ func (c *Context) SetColorMode(mode ColorMode) {
 	c.colorMode = mode
 	c.hasColorMode = true
 	c.app.requestRedraw(c.app.bounds())
+	c.app.requestFullRelayout()
 }
 
 func (c *Context) ResetColorMode() {
+	if !c.hasColorMode {
+		return
+	}
 	c.hasColorMode = false
+	c.app.requestRedraw(c.app.bounds())
+	c.app.requestFullRelayout()
 }
 
 func (c *Context) AppendLocales(locales []language.Tag) []language.Tag {
@@ -164,8 +174,55 @@ func (c *Context) SetAppLocales(locales []language.Tag) {
 
 	c.locales = append([]language.Tag(nil), locales...)
 	c.app.requestRedraw(c.app.bounds())
+	c.app.requestFullRelayout()
 }
 
 func (c *Context) AppSize() (int, int) {
 	return c.app.bounds().Dx(), c.app.bounds().Dy()
 }
+
+// FocusedWidget returns the currently focused widget.
+// FocusedWidget returns nil if there is no focused widget.
+func (c *Context) FocusedWidget() Widget {
+	return c.app.focusedWidget
+}
+
+func (c *Context) SetOnFocusChanged(f func(widget Widget)) {
+	c.app.onFocusChanged = f
+}
+
+func (c *Context) Input() *InputState {
+	return &c.app.inputState
+}
+
+func (c *Context) InputSource() InputSource {
+	return c.app.inputSource
+}
+
+func (c *Context) SetInputSource(source InputSource) {
+	if source == nil {
+		source = ebitenInputSource{}
+	}
+	c.app.inputSource = source
+}
+
+// Value returns the value associated with key in the app, or nil if there is no such value.
+//
+// Value is useful for a widget library to hold a state shared by widgets in the same app.
+// key should be a value of an unexported type to avoid collisions, like context.Context's keys.
+func (c *Context) Value(key any) any {
+	return c.values[key]
+}
+
+// SetValue associates value with key in the app.
+// If value is nil, the association is removed.
+func (c *Context) SetValue(key, value any) {
+	if value == nil {
+		delete(c.values, key)
+		return
+	}
+	if c.values == nil {
+		c.values = map[any]any{}
+	}
+	c.values[key] = value
+}
@@ -38,6 +38,9 @@ func (d *DefaultWidget) Size(context *Context) (int, int) {
 	return d.widgetState_.parent.Size(context)
 }
 
+func (*DefaultWidget) OnFocusChanged(context *Context, event FocusChangedEvent) {
+}
+
 func (d *DefaultWidget) widgetState() *widgetState {
 	return &d.widgetState_
 }
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EmbeddedOptions represents options for NewEmbedded.
+type EmbeddedOptions struct {
+	// DeviceScale is the device scale factor. If DeviceScale is 0, 1 is used.
+	DeviceScale float64
+
+	// AppScale is the app scale. If AppScale is 0, 1 is used.
+	AppScale float64
+
+	// InputSource is the source of inputs. If InputSource is nil, inputs are read from Ebitengine.
+	InputSource InputSource
+}
+
+// Embedded runs a widget tree inside an existing ebiten.Game.
+//
+// Embedded runs the same layout, input, update and partial redraw passes as Run,
+// on a sub-rectangle of an image given by the host game.
+// The host game must call Update in its Update and Draw in its Draw.
+//
+// Key inputs are delivered to the widget tree while the tree is active (see SetActive),
+// or while the cursor is inside the bounds.
+// A widget focused in an inactive tree doesn't receive key inputs.
+type Embedded struct {
+	app         *app
+	deviceScale float64
+	bounds      image.Rectangle
+	active      bool
+	input       embeddedInputSource
+
+	canvas *ebiten.Image
+}
+
+// NewEmbedded creates a new Embedded with the given root widget.
+func NewEmbedded(root Widget, options *EmbeddedOptions) *Embedded {
+	if options == nil {
+		options = &EmbeddedOptions{}
+	}
+
+	inputSource := options.InputSource
+	if inputSource == nil {
+		inputSource = ebitenInputSource{}
+	}
+
+	e := &Embedded{
+		app:         newApp(root, inputSource),
+		deviceScale: 1,
+	}
+	if options.DeviceScale > 0 {
+		e.deviceScale = options.DeviceScale
+	}
+	if options.AppScale > 0 {
+		e.app.context.appScaleMinus1 = options.AppScale - 1
+	}
+	return e
+}
+
+// Context returns the context of the app.
+func (e *Embedded) Context() *Context {
+	return &e.app.context
+}
+
+// Bounds returns the region of the host image where the widget tree is rendered.
+func (e *Embedded) Bounds() image.Rectangle {
+	return e.bounds
+}
+
+// SetBounds sets the region of the host image where the widget tree is rendered.
+//
+// The bounds are in the host image's coordinate.
+// Inputs are limited to the region.
+func (e *Embedded) SetBounds(bounds image.Rectangle) {
+	e.bounds = bounds
+	e.app.screenWidth = float64(bounds.Dx())
+	e.app.screenHeight = float64(bounds.Dy())
+}
+
+// SetDeviceScale sets the device scale factor.
+func (e *Embedded) SetDeviceScale(deviceScale float64) {
+	e.deviceScale = deviceScale
+}
+
+// SetActive sets whether the widget tree has the keyboard focus of the host game.
+//
+// While the tree is active, key inputs are delivered to the tree even when no widget in the tree is focused,
+// so Tab and Shift+Tab move the focus into the tree.
+// Deactivating the tree removes the focus from the widget in the tree.
+func (e *Embedded) SetActive(active bool) {
+	if e.active == active {
+		return
+	}
+	e.active = active
+	if !active {
+		e.app.setFocusedWidget(nil)
+	}
+}
+
+// IsActive reports whether the widget tree has the keyboard focus of the host game.
+func (e *Embedded) IsActive() bool {
+	return e.active
+}
+
+// FocusIn activates the widget tree and focuses the first focusable widget in the tree,
+// or the last one if backward is true.
+//
+// The host game can call FocusIn when Tab or Shift+Tab moves the host's focus to the tree.
+// FocusIn must be called after the first Update, as no widget is in the tree before that.
+func (e *Embedded) FocusIn(backward bool) {
+	e.SetActive(true)
+	e.app.setFocusedWidget(nil)
+	e.app.moveFocus(backward)
+}
+
+// Update proceeds one tick.
+func (e *Embedded) Update() error {
+	e.input.update(e.app.inputSource, e.bounds, e.active)
+	if err := e.app.update(e.deviceScale, &e.input); err != nil {
+		return err
+	}
+	if e.input.cursorIn {
+		ebiten.SetCursorShape(e.app.currentCursorShape)
+	}
+	return nil
+}
+
+// Draw renders the widget tree onto the region of dst.
+func (e *Embedded) Draw(dst *ebiten.Image) {
+	if e.bounds.Empty() {
+		return
+	}
+
+	if e.canvas != nil && (e.canvas.Bounds().Dx() != e.bounds.Dx() || e.canvas.Bounds().Dy() != e.bounds.Dy()) {
+		e.canvas.Deallocate()
+		e.canvas = nil
+	}
+	if e.canvas == nil {
+		e.canvas = ebiten.NewImage(e.bounds.Dx(), e.bounds.Dy())
+		e.app.requestRedraw(e.app.bounds())
+	}
+
+	// The host image is not preserved, so keep the rendering result in the canvas and redraw only the invalidated regions.
+	for _, region := range e.app.invalidatedRegions.rects {
+		e.canvas.SubImage(region).(*ebiten.Image).Clear()
+	}
+	e.app.draw(e.canvas)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(e.bounds.Min.X), float64(e.bounds.Min.Y))
+	dst.DrawImage(e.canvas, op)
+}
+
+type embeddedInputSource struct {
+	source   InputSource
+	bounds   image.Rectangle
+	cursorIn bool
+	active   bool
+
+	sourceMouseButtons [ebiten.MouseButtonMax + 1]bool
+	mouseButtons       [ebiten.MouseButtonMax + 1]bool
+}
+
+func (e *embeddedInputSource) update(source InputSource, bounds image.Rectangle, active bool) {
+	e.source = source
+	e.bounds = bounds
+	e.cursorIn = image.Pt(source.CursorPosition()).In(bounds)
+	e.active = active || e.cursorIn
+
+	// A mouse button is treated as pressed only when the press started inside the region.
+	for b := range e.mouseButtons {
+		pressed := source.IsMouseButtonPressed(ebiten.MouseButton(b))
+		switch {
+		case !pressed:
+			e.mouseButtons[b] = false
+		case !e.sourceMouseButtons[b] && e.cursorIn:
+			e.mouseButtons[b] = true
+		}
+		e.sourceMouseButtons[b] = pressed
+	}
+}
+
+func (e *embeddedInputSource) CursorPosition() (int, int) {
+	x, y := e.source.CursorPosition()
+	return x - e.bounds.Min.X, y - e.bounds.Min.Y
+}
+
+func (e *embeddedInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return e.mouseButtons[button]
+}
+
+func (e *embeddedInputSource) Wheel() (float64, float64) {
+	if !e.cursorIn {
+		return 0, 0
+	}
+	return e.source.Wheel()
+}
+
+func (e *embeddedInputSource) IsKeyPressed(key ebiten.Key) bool {
+	// Keys are delivered only while the tree is active.
+	if !e.active {
+		return false
+	}
+	return e.source.IsKeyPressed(key)
+}
+
+func (e *embeddedInputSource) AppendInputChars(runes []rune) []rune {
+	if !e.active {
+		return runes
+	}
+	return e.source.AppendInputChars(runes)
+}
+
+func (e *embeddedInputSource) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	origLen := len(touches)
+	touches = e.source.AppendTouchIDs(touches)
+	n := origLen
+	for _, id := range touches[origLen:] {
+		if !image.Pt(e.source.TouchPosition(id)).In(e.bounds) {
+			continue
+		}
+		touches[n] = id
+		n++
+	}
+	return touches[:n]
+}
+
+func (e *embeddedInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	x, y := e.source.TouchPosition(id)
+	return x - e.bounds.Min.X, y - e.bounds.Min.Y
+}
+
+func (e *embeddedInputSource) isIMEAvailable() bool {
+	s, ok := e.source.(imeInputSource)
+	return ok && s.isIMEAvailable()
+}
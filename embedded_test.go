@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+type hostInputSource struct {
+	cursor image.Point
+	keys   map[ebiten.Key]bool
+}
+
+func (h *hostInputSource) CursorPosition() (int, int) {
+	return h.cursor.X, h.cursor.Y
+}
+
+func (h *hostInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return false
+}
+
+func (h *hostInputSource) Wheel() (float64, float64) {
+	return 0, 0
+}
+
+func (h *hostInputSource) IsKeyPressed(key ebiten.Key) bool {
+	return h.keys[key]
+}
+
+func (h *hostInputSource) AppendInputChars(runes []rune) []rune {
+	return runes
+}
+
+func (h *hostInputSource) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return touches
+}
+
+func (h *hostInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	return 0, 0
+}
+
+type focusableWidget struct {
+	guigui.DefaultWidget
+}
+
+func (f *focusableWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetFocusable(f, true)
+}
+
+func (f *focusableWidget) Size(context *guigui.Context) (int, int) {
+	return 50, 50
+}
+
+type embeddedRoot struct {
+	guigui.RootWidget
+
+	widgets [2]focusableWidget
+}
+
+func (e *embeddedRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for i := range e.widgets {
+		guigui.SetPosition(&e.widgets[i], guigui.Position(e).Add(image.Pt(60*i, 0)))
+		appender.AppendChildWidget(&e.widgets[i])
+	}
+}
+
+func TestEmbeddedKeyboardFocus(t *testing.T) {
+	source := &hostInputSource{
+		keys: map[ebiten.Key]bool{},
+	}
+	root := &embeddedRoot{}
+	e := guigui.NewEmbedded(root, &guigui.EmbeddedOptions{
+		InputSource: source,
+	})
+	e.SetBounds(image.Rect(100, 100, 300, 200))
+	if err := e.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressTab := func() {
+		t.Helper()
+		source.keys[ebiten.KeyTab] = true
+		if err := e.Update(); err != nil {
+			t.Fatal(err)
+		}
+		source.keys[ebiten.KeyTab] = false
+		if err := e.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	focused := func() int {
+		for i := range root.widgets {
+			if guigui.IsFocused(&root.widgets[i]) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// Keys are not delivered while the tree is inactive and the cursor is outside.
+	pressTab()
+	if got, want := focused(), -1; got != want {
+		t.Errorf("focused widget while inactive: got: %d, want: %d", got, want)
+	}
+
+	// Tab moves the focus into the active tree.
+	e.SetActive(true)
+	pressTab()
+	if got, want := focused(), 0; got != want {
+		t.Errorf("focused widget after Tab: got: %d, want: %d", got, want)
+	}
+
+	// Deactivating the tree removes the focus.
+	e.SetActive(false)
+	if got, want := focused(), -1; got != want {
+		t.Errorf("focused widget after SetActive(false): got: %d, want: %d", got, want)
+	}
+
+	// The host routes Shift+Tab into the tree.
+	e.FocusIn(true)
+	if !e.IsActive() {
+		t.Errorf("IsActive() after FocusIn: got: false, want: true")
+	}
+	if got, want := focused(), 1; got != want {
+		t.Errorf("focused widget after FocusIn(true): got: %d, want: %d", got, want)
+	}
+
+	// Keys are delivered while the cursor is inside the bounds.
+	e.SetActive(false)
+	source.cursor = image.Pt(250, 150)
+	pressTab()
+	if got, want := focused(), 0; got != want {
+		t.Errorf("focused widget after Tab with the cursor inside: got: %d, want: %d", got, want)
+	}
+
+	// Keys are not delivered to the focused widget after the cursor leaves the inactive tree.
+	source.cursor = image.Pt(0, 0)
+	pressTab()
+	if got, want := focused(), 0; got != want {
+		t.Errorf("focused widget after Tab with the cursor outside: got: %d, want: %d", got, want)
+	}
+
+	// Keys are not delivered to a widget focused after the host deactivated the tree.
+	e.SetActive(true)
+	e.SetActive(false)
+	guigui.Focus(&root.widgets[1])
+	if err := e.Update(); err != nil {
+		t.Fatal(err)
+	}
+	pressTab()
+	if got, want := focused(), 1; got != want {
+		t.Errorf("focused widget after Tab in the deactivated tree: got: %d, want: %d", got, want)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"github.com/xackery/guigui"
 	"github.com/xackery/guigui/basicwidget"
@@ -25,6 +24,9 @@ type Popups struct {
 
 	contextMenuPopupText          basicwidget.Text
 	contextMenuPopupClickHereText basicwidget.Text
+	menuBarText                   basicwidget.Text
+	menuBar                       basicwidget.MenuBar
+	showStatusBar                 bool
 
 	simplePopup            basicwidget.Popup
 	simplePopupTitleText   basicwidget.Text
@@ -64,6 +66,47 @@ func (p *Popups) Layout(context *guigui.Context, appender *guigui.ChildWidgetApp
 
 	p.contextMenuPopupText.SetText("Context Menu")
 	p.contextMenuPopupClickHereText.SetText("Click Here by the Right Button")
+	p.menuBarText.SetText("Menu Bar")
+	p.menuBar.SetMenus([]basicwidget.MenuBarMenu{
+		{
+			Title: "&File",
+			Items: []basicwidget.PopupMenuItem{
+				{
+					ID:       "new",
+					Text:     "New",
+					Shortcut: "Ctrl+N",
+				},
+				{
+					ID:       "open",
+					Text:     "Open...",
+					Shortcut: "Ctrl+O",
+				},
+				{
+					Separator: true,
+				},
+				{
+					ID:   "quit",
+					Text: "Quit",
+				},
+			},
+		},
+		{
+			Title: "&View",
+			Items: []basicwidget.PopupMenuItem{
+				{
+					ID:        "statusbar",
+					Text:      "Status Bar",
+					Checkable: true,
+					Checked:   p.showStatusBar,
+				},
+			},
+		},
+	})
+	p.menuBar.SetOnItemSelected(func(id string) {
+		if id == "statusbar" {
+			p.showStatusBar = !p.showStatusBar
+		}
+	})
 
 	p.forms[1].SetWidth(context, w-int(1*u))
 	p.forms[1].SetItems([]*basicwidget.FormItem{
@@ -71,6 +114,10 @@ func (p *Popups) Layout(context *guigui.Context, appender *guigui.ChildWidgetApp
 			PrimaryWidget:   &p.contextMenuPopupText,
 			SecondaryWidget: &p.contextMenuPopupClickHereText,
 		},
+		{
+			PrimaryWidget:   &p.menuBarText,
+			SecondaryWidget: &p.menuBar,
+		},
 	})
 	_, h := p.forms[0].Size(context)
 	pt.Y += h + int(0.5*u)
@@ -79,7 +126,8 @@ func (p *Popups) Layout(context *guigui.Context, appender *guigui.ChildWidgetApp
 
 	contentWidth := int(12 * u)
 	contentHeight := int(6 * u)
-	bounds := guigui.Bounds(&p.simplePopup)
+	appWidth, appHeight := context.AppSize()
+	bounds := image.Rect(0, 0, appWidth, appHeight)
 	contentPosition := image.Point{
 		X: bounds.Min.X + (bounds.Dx()-contentWidth)/2,
 		Y: bounds.Min.Y + (bounds.Dy()-contentHeight)/2,
@@ -110,13 +158,50 @@ func (p *Popups) Layout(context *guigui.Context, appender *guigui.ChildWidgetApp
 
 	appender.AppendChildWidget(&p.simplePopup)
 
-	p.contextMenuPopup.SetItemsByStrings([]string{"Item 1", "Item 2", "Item 3"})
+	p.contextMenuPopup.SetItems([]basicwidget.PopupMenuItem{
+		{
+			ID:       "item1",
+			Text:     "Item 1",
+			Shortcut: "Ctrl+1",
+		},
+		{
+			ID:       "item2",
+			Text:     "Item 2",
+			Disabled: true,
+		},
+		{
+			Separator: true,
+		},
+		{
+			ID:        "checkable",
+			Text:      "Checkable Item",
+			Checkable: true,
+			Checked:   p.contextMenuPopup.IsItemChecked("checkable"),
+		},
+		{
+			Text: "Submenu",
+			Submenu: []basicwidget.PopupMenuItem{
+				{
+					ID:         "radio1",
+					Text:       "Radio 1",
+					RadioGroup: "radio",
+					Checked:    !p.contextMenuPopup.IsItemChecked("radio2"),
+				},
+				{
+					ID:         "radio2",
+					Text:       "Radio 2",
+					RadioGroup: "radio",
+					Checked:    p.contextMenuPopup.IsItemChecked("radio2"),
+				},
+			},
+		},
+	})
 	appender.AppendChildWidget(&p.contextMenuPopup)
 }
 
 func (p *Popups) HandleInput(context *guigui.Context) guigui.HandleInputResult {
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		pt := image.Pt(ebiten.CursorPosition())
+	if context.Input().IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		pt := context.Input().CursorPosition()
 		if pt.In(guigui.VisibleBounds(&p.contextMenuPopupClickHereText)) {
 			guigui.SetPosition(&p.contextMenuPopup, pt)
 			p.contextMenuPopup.Open(context)
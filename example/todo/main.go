@@ -41,6 +41,7 @@ type TaskWidgets struct {
 type Root struct {
 	guigui.RootWidget
 
+	header       basicwidget.Flex
 	createButton basicwidget.TextButton
 	textField    basicwidget.TextField
 	taskWidgets  map[uuid.UUID]*TaskWidgets
@@ -52,29 +53,33 @@ type Root struct {
 func (r *Root) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
 	u := float64(basicwidget.UnitSize(context))
 
-	width, _ := r.Size(context)
-	w := width - int(6.5*u)
-	r.textField.SetSize(context, w, int(u))
 	r.textField.SetOnEnterPressed(func(text string) {
 		r.tryCreateTask()
 	})
-	{
-		guigui.SetPosition(&r.textField, guigui.Position(r).Add(image.Pt(int(0.5*u), int(0.5*u))))
-		appender.AppendChildWidget(&r.textField)
-	}
 
 	r.createButton.SetText("Create")
 	r.createButton.SetWidth(int(5 * u))
 	r.createButton.SetOnUp(func() {
 		r.tryCreateTask()
 	})
+
 	{
-		p := guigui.Position(r)
+		r.header.SetDirection(basicwidget.LayoutDirectionHorizontal)
+		r.header.SetGap(int(0.5 * u))
+		r.header.SetAlignment(basicwidget.LayoutAlignmentStretch)
+		r.header.SetItems([]basicwidget.FlexItem{
+			{
+				Widget: &r.textField,
+				Grow:   1,
+			},
+			{
+				Widget: &r.createButton,
+			},
+		})
 		w, _ := r.Size(context)
-		p.X += w - int(0.5*u) - int(5*u)
-		p.Y += int(0.5 * u)
-		guigui.SetPosition(&r.createButton, p)
-		appender.AppendChildWidget(&r.createButton)
+		r.header.SetSize(context, w-int(u), int(u))
+		guigui.SetPosition(&r.header, guigui.Position(r).Add(image.Pt(int(0.5*u), int(0.5*u))))
+		appender.AppendChildWidget(&r.header)
 	}
 
 	w, h := r.Size(context)
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SetFocusable sets whether the widget can be focused by keyboard navigation (Tab and Shift+Tab).
+//
+// A widget can be focused by Focus regardless of whether it is focusable.
+func SetFocusable(widget Widget, focusable bool) {
+	widget.widgetState().focusable = focusable
+}
+
+func IsFocusable(widget Widget) bool {
+	return widget.widgetState().focusable
+}
+
+// SetFocusTrap sets whether keyboard navigation is trapped in the widget while the widget is visible.
+//
+// While focus traps are visible, Tab and Shift+Tab visit only the focusable widgets in the topmost one.
+// If the topmost focus trap has no focusable widgets, the focus trap itself is focused so that the focus doesn't stay behind it.
+// A modal popup should be a focus trap.
+func SetFocusTrap(widget Widget, trap bool) {
+	widget.widgetState().focusTrap = trap
+}
+
+func IsFocusTrap(widget Widget) bool {
+	return widget.widgetState().focusTrap
+}
+
+// SetTabIndex sets the tab index of the widget.
+//
+// Focusable widgets with positive tab indices are visited first in ascending order of their tab indices.
+// Then, focusable widgets with a zero tab index are visited in the tree order.
+// Widgets with negative tab indices are skipped by keyboard navigation.
+// The default tab index is 0.
+func SetTabIndex(widget Widget, index int) {
+	widget.widgetState().tabIndex = index
+}
+
+func TabIndex(widget Widget) int {
+	return widget.widgetState().tabIndex
+}
+
+func (a *app) setFocusedWidget(widget Widget) {
+	if a.focusedWidget == widget {
+		return
+	}
+
+	oldWidget := a.focusedWidget
+	a.focusedWidget = widget
+	// The layout might depend on the focus, e.g. a text cursor.
+	if widget != nil {
+		RequestRedraw(widget)
+		RequestRelayout(widget)
+	}
+	if oldWidget != nil {
+		RequestRedraw(oldWidget)
+		RequestRelayout(oldWidget)
+	}
+
+	// Notify the widgets losing the focus first, and then the widgets gaining the focus, from the deepest ones.
+	// A handler might move the focus again. As each event is computed from the current focus and
+	// the last event for the widget, every widget gets consistent events even in that case.
+	for w := oldWidget; w != nil; w = w.widgetState().parent {
+		a.dispatchFocusChanged(w)
+	}
+	for w := widget; w != nil; w = w.widgetState().parent {
+		a.dispatchFocusChanged(w)
+	}
+
+	a.notifyFocusChanged()
+}
+
+func (a *app) dispatchFocusChanged(widget Widget) {
+	event := FocusChangedEvent{
+		Focused:     a.focusedWidget == widget,
+		FocusWithin: a.focusedWidget != nil && isAncestorOrSelf(widget, a.focusedWidget),
+	}
+	widgetState := widget.widgetState()
+	if widgetState.focusChangedEvent == event {
+		return
+	}
+	widgetState.focusChangedEvent = event
+	widget.OnFocusChanged(&a.context, event)
+}
+
+func (a *app) notifyFocusChanged() {
+	if a.onFocusChanged != nil {
+		a.onFocusChanged(a.focusedWidget)
+	}
+}
+
+func (a *app) handleFocusNavigation() {
+	if !a.inputState.IsKeyJustPressed(ebiten.KeyTab) {
+		return
+	}
+	a.moveFocus(a.inputState.IsKeyPressed(ebiten.KeyShift))
+}
+
+func (a *app) moveFocus(backward bool) {
+	root := a.root
+	if trap := a.topmostFocusTrap(); trap != nil {
+		root = trap
+	}
+	widgets := appendFocusableWidgets(nil, root)
+	if len(widgets) == 0 {
+		if root != a.root && !isAncestorOrSelf(root, a.focusedWidget) {
+			Focus(root)
+		}
+		return
+	}
+
+	// Focus stays in the topmost layer. For example, when a popup is open, only the widgets in the popup are visited.
+	maxZ := math.MinInt
+	for _, widget := range widgets {
+		maxZ = max(maxZ, z(widget))
+	}
+	widgets = slices.DeleteFunc(widgets, func(widget Widget) bool {
+		return z(widget) != maxZ
+	})
+
+	slices.SortStableFunc(widgets, func(a, b Widget) int {
+		return cmp.Compare(tabOrder(a), tabOrder(b))
+	})
+
+	// The current widget might not be focusable itself, e.g. when a child widget of a focusable widget is focused.
+	current := -1
+	for w := a.focusedWidget; w != nil && current < 0; w = w.widgetState().parent {
+		current = slices.Index(widgets, w)
+	}
+
+	var next int
+	switch {
+	case current < 0 && backward:
+		next = len(widgets) - 1
+	case current < 0:
+		next = 0
+	case backward:
+		next = (current - 1 + len(widgets)) % len(widgets)
+	default:
+		next = (current + 1) % len(widgets)
+	}
+	Focus(widgets[next])
+}
+
+// topmostFocusTrap returns the visible focus trap drawn at the top, or nil if there is no such widget.
+func (a *app) topmostFocusTrap() Widget {
+	for i := len(a.zOrder) - 1; i >= 0; i-- {
+		widget := a.widgets[a.zOrder[i]]
+		if widget.widgetState().focusTrap && IsVisible(widget) {
+			return widget
+		}
+	}
+	return nil
+}
+
+func tabOrder(widget Widget) int {
+	if i := widget.widgetState().tabIndex; i > 0 {
+		return i
+	}
+	return math.MaxInt
+}
+
+func appendFocusableWidgets(widgets []Widget, widget Widget) []Widget {
+	widgetState := widget.widgetState()
+	if widgetState.hidden || widgetState.disabled {
+		return widgets
+	}
+	if widgetState.focusable && widgetState.tabIndex >= 0 {
+		widgets = append(widgets, widget)
+	}
+	for _, child := range widgetState.children {
+		widgets = appendFocusableWidgets(widgets, child)
+	}
+	return widgets
+}
@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/atotto/clipboard v0.1.4
+	github.com/go-text/typesetting v0.3.0
 	github.com/google/uuid v1.6.0
 	github.com/hajimehoshi/ebiten/v2 v2.9.0-alpha.4
 	github.com/hajimehoshi/guigui v0.0.0-20250309144559-401b6e0bfcf9
@@ -17,7 +18,6 @@ require (
 	github.com/ebitengine/gomobile v0.0.0-20250209143333-6071a2a2351c // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.9.0-alpha.2 // indirect
-	github.com/go-text/typesetting v0.3.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
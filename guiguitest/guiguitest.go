@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+// Package guiguitest provides a driver to test guigui widgets without a window or a GPU.
+package guiguitest
+
+import (
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+)
+
+// Driver drives a widget tree headlessly.
+//
+// Inputs given to a Driver are delivered to the widgets at the next Step.
+type Driver struct {
+	headless *guigui.Headless
+	input    input
+}
+
+// New creates a new Driver for the given root widget.
+// width and height are in device-independent pixels, and scale is the device scale factor.
+func New(root guigui.Widget, width, height int, scale float64) *Driver {
+	d := &Driver{}
+	d.input.cursorX = -1
+	d.input.cursorY = -1
+	d.headless = guigui.NewHeadless(root, &guigui.HeadlessOptions{
+		Width:       width,
+		Height:      height,
+		DeviceScale: scale,
+		InputSource: &d.input,
+	})
+	return d
+}
+
+// Context returns the context of the app.
+func (d *Driver) Context() *guigui.Context {
+	return d.headless.Context()
+}
+
+// Root returns the root widget.
+func (d *Driver) Root() guigui.Widget {
+	return d.headless.Root()
+}
+
+// SetSize sets the app size in device-independent pixels.
+func (d *Driver) SetSize(width, height int) {
+	d.headless.SetSize(width, height)
+}
+
+// Step proceeds one tick.
+func (d *Driver) Step() error {
+	if err := d.headless.Update(); err != nil {
+		return err
+	}
+	d.input.inputChars = slices.Delete(d.input.inputChars, 0, len(d.input.inputChars))
+	d.input.wheelX = 0
+	d.input.wheelY = 0
+	return nil
+}
+
+// StepN proceeds n ticks.
+func (d *Driver) StepN(n int) error {
+	for range n {
+		if err := d.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveCursor moves the cursor to the given position in the screen coordinate.
+func (d *Driver) MoveCursor(x, y int) {
+	d.input.cursorX = x
+	d.input.cursorY = y
+}
+
+// PressMouseButton starts pressing the given mouse button.
+func (d *Driver) PressMouseButton(button ebiten.MouseButton) {
+	d.input.mouseButtons[button] = true
+}
+
+// ReleaseMouseButton stops pressing the given mouse button.
+func (d *Driver) ReleaseMouseButton(button ebiten.MouseButton) {
+	d.input.mouseButtons[button] = false
+}
+
+// Click moves the cursor to the given position, and presses and releases the left mouse button.
+// Click proceeds two ticks.
+func (d *Driver) Click(x, y int) error {
+	d.MoveCursor(x, y)
+	d.PressMouseButton(ebiten.MouseButtonLeft)
+	if err := d.Step(); err != nil {
+		return err
+	}
+	d.ReleaseMouseButton(ebiten.MouseButtonLeft)
+	if err := d.Step(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Scroll enqueues the given wheel movement for the next Step.
+func (d *Driver) Scroll(dx, dy float64) {
+	d.input.wheelX += dx
+	d.input.wheelY += dy
+}
+
+// PressKey starts pressing the given key.
+func (d *Driver) PressKey(key ebiten.Key) {
+	d.input.keys[key] = true
+}
+
+// ReleaseKey stops pressing the given key.
+func (d *Driver) ReleaseKey(key ebiten.Key) {
+	d.input.keys[key] = false
+}
+
+// TypeText enqueues the given text as input characters for the next Step.
+func (d *Driver) TypeText(text string) {
+	d.input.inputChars = append(d.input.inputChars, []rune(text)...)
+}
+
+// Touch starts or moves the touch of the given ID at the given position.
+func (d *Driver) Touch(id ebiten.TouchID, x, y int) {
+	if d.input.touches == nil {
+		d.input.touches = map[ebiten.TouchID]image.Point{}
+	}
+	d.input.touches[id] = image.Pt(x, y)
+}
+
+// ReleaseTouch ends the touch of the given ID.
+func (d *Driver) ReleaseTouch(id ebiten.TouchID) {
+	delete(d.input.touches, id)
+}
+
+// Widgets returns all the widgets in the tree in the depth-first order.
+func (d *Driver) Widgets() []guigui.Widget {
+	var widgets []guigui.Widget
+	var f func(widget guigui.Widget)
+	f = func(widget guigui.Widget) {
+		widgets = append(widgets, widget)
+		for _, child := range guigui.ChildWidgets(widget) {
+			f(child)
+		}
+	}
+	f(d.Root())
+	return widgets
+}
+
+// FocusedWidget returns the currently focused widget, or nil if there is none.
+func (d *Driver) FocusedWidget() guigui.Widget {
+	return d.headless.FocusedWidget()
+}
+
+// InvalidatedRegion returns the region invalidated at the last Step.
+func (d *Driver) InvalidatedRegion() image.Rectangle {
+	return d.headless.InvalidatedRegion()
+}
+
+// InvalidatedRegions returns the non-overlapping regions invalidated at the last Step.
+func (d *Driver) InvalidatedRegions() []image.Rectangle {
+	return d.headless.InvalidatedRegions()
+}
+
+// CursorShape returns the cursor shape determined at the last Step.
+func (d *Driver) CursorShape() ebiten.CursorShapeType {
+	return d.headless.CursorShape()
+}
+
+type input struct {
+	cursorX      int
+	cursorY      int
+	mouseButtons [ebiten.MouseButtonMax + 1]bool
+	wheelX       float64
+	wheelY       float64
+	keys         [ebiten.KeyMax + 1]bool
+	inputChars   []rune
+	touches      map[ebiten.TouchID]image.Point
+}
+
+func (i *input) CursorPosition() (int, int) {
+	return i.cursorX, i.cursorY
+}
+
+func (i *input) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return i.mouseButtons[button]
+}
+
+func (i *input) Wheel() (float64, float64) {
+	return i.wheelX, i.wheelY
+}
+
+func (i *input) IsKeyPressed(key ebiten.Key) bool {
+	return i.keys[key]
+}
+
+func (i *input) AppendInputChars(runes []rune) []rune {
+	return append(runes, i.inputChars...)
+}
+
+func (i *input) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	origLen := len(touches)
+	for id := range i.touches {
+		touches = append(touches, id)
+	}
+	slices.Sort(touches[origLen:])
+	return touches
+}
+
+func (i *input) TouchPosition(id ebiten.TouchID) (int, int) {
+	p := i.touches[id]
+	return p.X, p.Y
+}
@@ -0,0 +1,618 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guiguitest_test
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/xackery/guigui"
+	"github.com/xackery/guigui/guiguitest"
+)
+
+type root struct {
+	guigui.RootWidget
+
+	mouseOverlay guigui.MouseOverlay
+
+	upCount int
+}
+
+func (r *root) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	r.mouseOverlay.SetOnUp(func(mouseButton ebiten.MouseButton, cursorPosition image.Point) {
+		r.upCount++
+	})
+	guigui.SetPosition(&r.mouseOverlay, image.Pt(10, 10))
+	appender.AppendChildWidget(&r.mouseOverlay)
+}
+
+func TestClick(t *testing.T) {
+	r := &root{}
+	d := guiguitest.New(r, 320, 240, 1)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.InvalidatedRegion(), image.Rect(0, 0, 320, 240); got != want {
+		t.Errorf("InvalidatedRegion(): got: %v, want: %v", got, want)
+	}
+	if got, want := len(d.Widgets()), 2; got != want {
+		t.Errorf("len(Widgets()): got: %d, want: %d", got, want)
+	}
+	if got, want := guigui.Bounds(&r.mouseOverlay), image.Rect(10, 10, 330, 250); got != want {
+		t.Errorf("guigui.Bounds(): got: %v, want: %v", got, want)
+	}
+
+	if err := d.Click(20, 20); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.upCount, 1; got != want {
+		t.Errorf("upCount: got: %d, want: %d", got, want)
+	}
+	if got, want := d.FocusedWidget(), guigui.Widget(&r.mouseOverlay); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+
+	if err := d.Click(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.upCount, 1; got != want {
+		t.Errorf("upCount: got: %d, want: %d", got, want)
+	}
+}
+
+func primaryKey() ebiten.Key {
+	if runtime.GOOS == "darwin" {
+		return ebiten.KeyMeta
+	}
+	return ebiten.KeyControl
+}
+
+// keyWidget is a focusable widget handling the S key while it is focused.
+type keyWidget struct {
+	guigui.DefaultWidget
+
+	count int
+}
+
+func (k *keyWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetFocusable(k, true)
+}
+
+func (k *keyWidget) HandleInput(context *guigui.Context) guigui.HandleInputResult {
+	if guigui.IsFocused(k) && context.Input().IsKeyJustPressed(ebiten.KeyS) {
+		k.count++
+		return guigui.HandleInputByWidget(k)
+	}
+	return guigui.HandleInputResult{}
+}
+
+func (k *keyWidget) Size(context *guigui.Context) (int, int) {
+	return 100, 100
+}
+
+type commandRoot struct {
+	guigui.RootWidget
+
+	keyWidget keyWidget
+}
+
+func (c *commandRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetPosition(&c.keyWidget, image.Pt(0, 0))
+	appender.AppendChildWidget(&c.keyWidget)
+}
+
+func TestCommandHandledByFocusedWidget(t *testing.T) {
+	r := &commandRoot{}
+	d := guiguitest.New(r, 320, 240, 1)
+	var commandCount int
+	if err := d.Context().RegisterCommand(guigui.Command{
+		Name: "save",
+		Action: func() {
+			commandCount++
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Context().BindKey(guigui.KeyChord{Key: ebiten.KeyS, Modifiers: guigui.ModifierPrimary}, "save"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressPrimaryS := func() {
+		t.Helper()
+		d.PressKey(primaryKey())
+		d.PressKey(ebiten.KeyS)
+		if err := d.StepN(3); err != nil {
+			t.Fatal(err)
+		}
+		d.ReleaseKey(primaryKey())
+		d.ReleaseKey(ebiten.KeyS)
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The focused widget takes precedence over the command.
+	guigui.Focus(&r.keyWidget)
+	pressPrimaryS()
+	if got, want := r.keyWidget.count, 1; got != want {
+		t.Errorf("keyWidget.count: got: %d, want: %d", got, want)
+	}
+	if got, want := commandCount, 0; got != want {
+		t.Errorf("commandCount: got: %d, want: %d", got, want)
+	}
+
+	// Without the focus, the command is executed.
+	guigui.Blur(&r.keyWidget)
+	pressPrimaryS()
+	if got, want := r.keyWidget.count, 1; got != want {
+		t.Errorf("keyWidget.count: got: %d, want: %d", got, want)
+	}
+	if got, want := commandCount, 1; got != want {
+		t.Errorf("commandCount: got: %d, want: %d", got, want)
+	}
+}
+
+// trapWidget is a popup trapping the keyboard navigation.
+type trapWidget struct {
+	guigui.DefaultWidget
+
+	children []guigui.Widget
+}
+
+func (t *trapWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetFocusTrap(t, true)
+	for _, child := range t.children {
+		guigui.SetPosition(child, guigui.Position(t))
+		appender.AppendChildWidget(child)
+	}
+}
+
+func (t *trapWidget) IsPopup() bool {
+	return true
+}
+
+func (t *trapWidget) Size(context *guigui.Context) (int, int) {
+	return 100, 100
+}
+
+type trapRoot struct {
+	guigui.RootWidget
+
+	keyWidget  keyWidget
+	trapWidget trapWidget
+}
+
+func (t *trapRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	guigui.SetPosition(&t.keyWidget, image.Pt(0, 0))
+	appender.AppendChildWidget(&t.keyWidget)
+	guigui.SetPosition(&t.trapWidget, image.Pt(100, 0))
+	appender.AppendChildWidget(&t.trapWidget)
+}
+
+func TestFocusTrap(t *testing.T) {
+	r := &trapRoot{}
+	guigui.Hide(&r.trapWidget)
+	d := guiguitest.New(r, 320, 240, 1)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressTab := func() {
+		t.Helper()
+		d.PressKey(ebiten.KeyTab)
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+		d.ReleaseKey(ebiten.KeyTab)
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pressTab()
+	if got, want := d.FocusedWidget(), guigui.Widget(&r.keyWidget); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+
+	// A visible trap without focusable widgets takes the focus.
+	guigui.Show(&r.trapWidget)
+	for range 2 {
+		pressTab()
+		if got, want := d.FocusedWidget(), guigui.Widget(&r.trapWidget); got != want {
+			t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+		}
+	}
+
+	// Only the focusable widgets in the trap are visited.
+	var child0, child1 keyWidget
+	r.trapWidget.children = []guigui.Widget{&child0, &child1}
+	guigui.RequestRelayout(&r.trapWidget)
+	for _, want := range []guigui.Widget{&child0, &child1, &child0} {
+		pressTab()
+		if got := d.FocusedWidget(); got != want {
+			t.Errorf("FocusedWidget(): got: %p, want: %p", got, want)
+		}
+	}
+
+	// Shift+Tab also stays in the trap.
+	for _, want := range []guigui.Widget{&child1, &child0, &child1} {
+		d.PressKey(ebiten.KeyShift)
+		pressTab()
+		d.ReleaseKey(ebiten.KeyShift)
+		if got := d.FocusedWidget(); got != want {
+			t.Errorf("FocusedWidget(): got: %p, want: %p", got, want)
+		}
+	}
+
+	// The trap is released when it is hidden.
+	guigui.Hide(&r.trapWidget)
+	pressTab()
+	if got, want := d.FocusedWidget(), guigui.Widget(&r.keyWidget); got != want {
+		t.Errorf("FocusedWidget(): got: %T, want: %T", got, want)
+	}
+}
+
+func TestDriversDoNotShareState(t *testing.T) {
+	r0 := &commandRoot{}
+	r1 := &commandRoot{}
+	d0 := guiguitest.New(r0, 320, 240, 1)
+	d1 := guiguitest.New(r1, 640, 480, 2)
+
+	type key struct{}
+	d0.Context().SetValue(key{}, "foo")
+	if got := d1.Context().Value(key{}); got != nil {
+		t.Errorf("d1.Context().Value(): got: %v, want: nil", got)
+	}
+
+	// The same command name can be registered to each app.
+	for _, d := range []*guiguitest.Driver{d0, d1} {
+		if err := d.Context().RegisterCommand(guigui.Command{Name: "save", Action: func() {}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, d := range []*guiguitest.Driver{d0, d1} {
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := d0.Context().Scale(), 1.0; got != want {
+		t.Errorf("d0.Context().Scale(): got: %v, want: %v", got, want)
+	}
+	if got, want := d1.Context().Scale(), 2.0; got != want {
+		t.Errorf("d1.Context().Scale(): got: %v, want: %v", got, want)
+	}
+	// The region is in device pixels.
+	if got, want := d1.InvalidatedRegion(), image.Rect(0, 0, 1280, 960); got != want {
+		t.Errorf("d1.InvalidatedRegion(): got: %v, want: %v", got, want)
+	}
+
+	// The focus and the key inputs are per app.
+	guigui.Focus(&r0.keyWidget)
+	d0.PressKey(ebiten.KeyS)
+	d1.PressKey(ebiten.KeyS)
+	for _, d := range []*guiguitest.Driver{d0, d1} {
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := d0.FocusedWidget(), guigui.Widget(&r0.keyWidget); got != want {
+		t.Errorf("d0.FocusedWidget(): got: %T, want: %T", got, want)
+	}
+	if got := d1.FocusedWidget(); got != nil {
+		t.Errorf("d1.FocusedWidget(): got: %T, want: nil", got)
+	}
+	if got, want := r0.keyWidget.count, 1; got != want {
+		t.Errorf("r0.keyWidget.count: got: %d, want: %d", got, want)
+	}
+	if got, want := r1.keyWidget.count, 0; got != want {
+		t.Errorf("r1.keyWidget.count: got: %d, want: %d", got, want)
+	}
+
+	// A widget not in any app is still measured.
+	var detached keyWidget
+	guigui.SetPosition(&detached, image.Pt(10, 20))
+	if got, want := guigui.Bounds(&detached), image.Rect(10, 20, 110, 120); got != want {
+		t.Errorf("guigui.Bounds(): got: %v, want: %v", got, want)
+	}
+}
+
+type tabIndexRoot struct {
+	guigui.RootWidget
+
+	keyWidgets [4]keyWidget
+}
+
+func (t *tabIndexRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for i := range t.keyWidgets {
+		guigui.SetPosition(&t.keyWidgets[i], image.Pt(i*100, 0))
+		appender.AppendChildWidget(&t.keyWidgets[i])
+	}
+}
+
+func TestTabIndex(t *testing.T) {
+	r := &tabIndexRoot{}
+	d := guiguitest.New(r, 320, 240, 1)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.SetTabIndex(&r.keyWidgets[1], 2)
+	guigui.SetTabIndex(&r.keyWidgets[2], 1)
+	guigui.SetTabIndex(&r.keyWidgets[3], -1)
+
+	pressTab := func(backward bool) {
+		t.Helper()
+		if backward {
+			d.PressKey(ebiten.KeyShift)
+		}
+		d.PressKey(ebiten.KeyTab)
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+		d.ReleaseKey(ebiten.KeyTab)
+		d.ReleaseKey(ebiten.KeyShift)
+		if err := d.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Positive tab indices come first in ascending order, then zero. A negative tab index is skipped.
+	for _, want := range []int{2, 1, 0, 2} {
+		pressTab(false)
+		if got, want := d.FocusedWidget(), guigui.Widget(&r.keyWidgets[want]); got != want {
+			t.Errorf("FocusedWidget(): got: %p, want: %p", got, want)
+		}
+	}
+	for _, want := range []int{0, 1, 2, 0} {
+		pressTab(true)
+		if got, want := d.FocusedWidget(), guigui.Widget(&r.keyWidgets[want]); got != want {
+			t.Errorf("FocusedWidget() with Shift: got: %p, want: %p", got, want)
+		}
+	}
+}
+
+// focusEventWidget records the focus change events of itself.
+type focusEventWidget struct {
+	guigui.DefaultWidget
+
+	name     string
+	log      *[]string
+	children []guigui.Widget
+}
+
+func (f *focusEventWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for _, child := range f.children {
+		guigui.SetPosition(child, guigui.Position(f))
+		appender.AppendChildWidget(child)
+	}
+}
+
+func (f *focusEventWidget) Size(context *guigui.Context) (int, int) {
+	return 100, 100
+}
+
+func (f *focusEventWidget) OnFocusChanged(context *guigui.Context, event guigui.FocusChangedEvent) {
+	*f.log = append(*f.log, fmt.Sprintf("%s:%t:%t", f.name, event.Focused, event.FocusWithin))
+}
+
+type focusEventRoot struct {
+	guigui.RootWidget
+
+	containers []*focusEventWidget
+}
+
+func (f *focusEventRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for i, c := range f.containers {
+		guigui.SetPosition(c, image.Pt(i*100, 0))
+		appender.AppendChildWidget(c)
+	}
+}
+
+func TestFocusChangedEvent(t *testing.T) {
+	var log []string
+	a0 := &focusEventWidget{name: "a0", log: &log}
+	a1 := &focusEventWidget{name: "a1", log: &log}
+	a := &focusEventWidget{name: "a", log: &log, children: []guigui.Widget{a0, a1}}
+	b0 := &focusEventWidget{name: "b0", log: &log}
+	b := &focusEventWidget{name: "b", log: &log, children: []guigui.Widget{b0}}
+	r := &focusEventRoot{containers: []*focusEventWidget{a, b}}
+	d := guiguitest.New(r, 320, 240, 1)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		change func()
+		want   []string
+	}{
+		{
+			name:   "focus",
+			change: func() { guigui.Focus(a0) },
+			// The widget gaining the focus is notified first, and then its ancestors.
+			want: []string{"a0:true:true", "a:false:true"},
+		},
+		{
+			name:   "sibling",
+			change: func() { guigui.Focus(a1) },
+			// The common ancestor keeps the focus within, and is not notified.
+			want: []string{"a0:false:false", "a1:true:true"},
+		},
+		{
+			name:   "another subtree",
+			change: func() { guigui.Focus(b0) },
+			// The widgets losing the focus are notified before the widgets gaining the focus.
+			want: []string{"a1:false:false", "a:false:false", "b0:true:true", "b:false:true"},
+		},
+		{
+			name:   "ancestor",
+			change: func() { guigui.Focus(b) },
+			want:   []string{"b0:false:false", "b:true:true"},
+		},
+		{
+			name:   "same",
+			change: func() { guigui.Focus(b) },
+			want:   nil,
+		},
+		{
+			name:   "blur",
+			change: func() { guigui.Blur(b) },
+			want:   []string{"b:false:false"},
+		},
+	}
+	for _, tc := range testCases {
+		log = nil
+		tc.change()
+		if !slices.Equal(log, tc.want) {
+			t.Errorf("%s: events: got: %v, want: %v", tc.name, log, tc.want)
+		}
+	}
+}
+
+// accessibleWidget is a widget exposing the given accessibility.
+type accessibleWidget struct {
+	guigui.DefaultWidget
+
+	accessibility guigui.Accessibility
+	children      []guigui.Widget
+
+	pressCount int
+}
+
+func (a *accessibleWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for _, child := range a.children {
+		guigui.SetPosition(child, guigui.Position(a))
+		appender.AppendChildWidget(child)
+	}
+}
+
+func (a *accessibleWidget) Size(context *guigui.Context) (int, int) {
+	return 100, 100
+}
+
+func (a *accessibleWidget) Accessibility(context *guigui.Context) guigui.Accessibility {
+	return a.accessibility
+}
+
+func (a *accessibleWidget) PerformAccessibilityAction(context *guigui.Context, action guigui.AccessibilityAction) bool {
+	if action != guigui.AccessibilityActionPress {
+		return false
+	}
+	a.pressCount++
+	return true
+}
+
+// containerWidget is a widget without accessibility.
+type containerWidget struct {
+	guigui.DefaultWidget
+
+	children []guigui.Widget
+}
+
+func (c *containerWidget) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for _, child := range c.children {
+		guigui.SetPosition(child, guigui.Position(c))
+		appender.AppendChildWidget(child)
+	}
+}
+
+type accessibilityRoot struct {
+	guigui.RootWidget
+
+	children []guigui.Widget
+}
+
+func (a *accessibilityRoot) Layout(context *guigui.Context, appender *guigui.ChildWidgetAppender) {
+	for i, child := range a.children {
+		guigui.SetPosition(child, image.Pt(i*100, 0))
+		appender.AppendChildWidget(child)
+	}
+}
+
+func TestAccessibilitySnapshot(t *testing.T) {
+	ok := &accessibleWidget{accessibility: guigui.Accessibility{Role: guigui.AccessibilityRoleButton, Label: "OK"}}
+	hidden := &accessibleWidget{accessibility: guigui.Accessibility{Role: guigui.AccessibilityRoleButton, Label: "Hidden"}}
+	group := &accessibleWidget{
+		accessibility: guigui.Accessibility{Role: guigui.AccessibilityRoleGroup, Label: "Group"},
+		children: []guigui.Widget{
+			&containerWidget{children: []guigui.Widget{ok, hidden}},
+		},
+	}
+	leafText := &accessibleWidget{accessibility: guigui.Accessibility{Role: guigui.AccessibilityRoleText, Label: "Inner"}}
+	leaf := &accessibleWidget{
+		accessibility: guigui.Accessibility{Role: guigui.AccessibilityRoleButton, Label: "Leaf", Leaf: true},
+		children:      []guigui.Widget{leafText},
+	}
+	guigui.Hide(hidden)
+	d := guiguitest.New(&accessibilityRoot{children: []guigui.Widget{group, leaf}}, 320, 240, 1)
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	guigui.Focus(leafText)
+
+	type node struct {
+		role     guigui.AccessibilityRole
+		label    string
+		focused  bool
+		children []node
+	}
+	var convert func(n guigui.AccessibilityNode) node
+	convert = func(n guigui.AccessibilityNode) node {
+		r := node{role: n.Role, label: n.Label, focused: n.Focused}
+		for _, c := range n.Children {
+			r.children = append(r.children, convert(c))
+		}
+		return r
+	}
+
+	// The children of a widget without accessibility are hoisted, and hidden widgets and descendants of leaves are excluded.
+	// The focus on a descendant of a leaf is the focus on the leaf.
+	snapshot := d.Context().AccessibilitySnapshot()
+	want := node{
+		role: guigui.AccessibilityRoleWindow,
+		children: []node{
+			{
+				role:  guigui.AccessibilityRoleGroup,
+				label: "Group",
+				children: []node{
+					{role: guigui.AccessibilityRoleButton, label: "OK"},
+				},
+			},
+			{role: guigui.AccessibilityRoleButton, label: "Leaf", focused: true},
+		},
+	}
+	if got := convert(snapshot); !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot: got: %+v, want: %+v", got, want)
+	}
+	if got, want := snapshot.Children[0].Children[0].Bounds, image.Rect(0, 0, 100, 100); got != want {
+		t.Errorf("Bounds: got: %v, want: %v", got, want)
+	}
+
+	// An action is performed on the widget of the node ID.
+	id := snapshot.Children[0].Children[0].ID
+	if !d.Context().PerformAccessibilityAction(id, guigui.AccessibilityActionPress) {
+		t.Errorf("PerformAccessibilityAction(%d, press): got: false, want: true", id)
+	}
+	if got, want := ok.pressCount, 1; got != want {
+		t.Errorf("pressCount: got: %d, want: %d", got, want)
+	}
+	if !d.Context().PerformAccessibilityAction(id, guigui.AccessibilityActionFocus) {
+		t.Errorf("PerformAccessibilityAction(%d, focus): got: false, want: true", id)
+	}
+	if got, want := d.FocusedWidget(), guigui.Widget(ok); got != want {
+		t.Errorf("FocusedWidget(): got: %p, want: %p", got, want)
+	}
+	if d.Context().PerformAccessibilityAction(0, guigui.AccessibilityActionPress) {
+		t.Errorf("PerformAccessibilityAction(0, press): got: true, want: false")
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// HeadlessOptions represents options for NewHeadless.
+type HeadlessOptions struct {
+	// Width and Height are the app size in device-independent pixels.
+	Width  int
+	Height int
+
+	// DeviceScale is the device scale factor. If DeviceScale is 0, 1 is used.
+	DeviceScale float64
+
+	// AppScale is the app scale. If AppScale is 0, 1 is used.
+	AppScale float64
+
+	// InputSource is the source of inputs. If InputSource is nil, no inputs are given.
+	InputSource InputSource
+}
+
+// Headless runs a widget tree without a window or a GPU.
+//
+// Headless runs the same layout, input and update passes as Run, but never draws anything.
+// Headless is mainly for testing. See also the guiguitest package.
+type Headless struct {
+	app         *app
+	deviceScale float64
+
+	lastInvalidatedRegions []image.Rectangle
+}
+
+// NewHeadless creates a new Headless with the given root widget.
+func NewHeadless(root Widget, options *HeadlessOptions) *Headless {
+	if options == nil {
+		options = &HeadlessOptions{}
+	}
+
+	inputSource := options.InputSource
+	if inputSource == nil {
+		inputSource = noInputSource{}
+	}
+
+	h := &Headless{
+		app:         newApp(root, inputSource),
+		deviceScale: 1,
+	}
+	if options.DeviceScale > 0 {
+		h.deviceScale = options.DeviceScale
+	}
+	if options.AppScale > 0 {
+		h.app.context.appScaleMinus1 = options.AppScale - 1
+	}
+	h.app.setOutsideSize(float64(options.Width), float64(options.Height), h.deviceScale)
+	return h
+}
+
+// Context returns the context of the app.
+func (h *Headless) Context() *Context {
+	return &h.app.context
+}
+
+// SetSize sets the app size in device-independent pixels.
+func (h *Headless) SetSize(width, height int) {
+	h.app.setOutsideSize(float64(width), float64(height), h.deviceScale)
+}
+
+// Update proceeds one tick.
+//
+// As Headless doesn't render anything, the regions invalidated in the tick are discarded after Update.
+// Use InvalidatedRegion or InvalidatedRegions to get them.
+func (h *Headless) Update() error {
+	if err := h.app.update(h.deviceScale, h.app.inputSource); err != nil {
+		return err
+	}
+	h.lastInvalidatedRegions = append(h.lastInvalidatedRegions[:0], h.app.invalidatedRegions.rects...)
+	h.app.resetInvalidatedRegions()
+	return nil
+}
+
+// Root returns the root widget.
+func (h *Headless) Root() Widget {
+	return h.app.root
+}
+
+// FocusedWidget returns the currently focused widget.
+// FocusedWidget returns nil if there is no focused widget.
+func (h *Headless) FocusedWidget() Widget {
+	return h.app.focusedWidget
+}
+
+// InvalidatedRegion returns the region that would have been redrawn by the last Update.
+func (h *Headless) InvalidatedRegion() image.Rectangle {
+	var region image.Rectangle
+	for _, r := range h.lastInvalidatedRegions {
+		region = region.Union(r)
+	}
+	return region
+}
+
+// InvalidatedRegions returns the non-overlapping regions that would have been redrawn separately by the last Update.
+//
+// The returned slice is valid until the next Update.
+func (h *Headless) InvalidatedRegions() []image.Rectangle {
+	return h.lastInvalidatedRegions
+}
+
+// CursorShape returns the cursor shape determined by the last Update.
+func (h *Headless) CursorShape() ebiten.CursorShapeType {
+	return h.app.currentCursorShape
+}
+
+type noInputSource struct{}
+
+func (noInputSource) CursorPosition() (int, int) {
+	return -1, -1
+}
+
+func (noInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return false
+}
+
+func (noInputSource) Wheel() (float64, float64) {
+	return 0, 0
+}
+
+func (noInputSource) IsKeyPressed(key ebiten.Key) bool {
+	return false
+}
+
+func (noInputSource) AppendInputChars(runes []rune) []rune {
+	return runes
+}
+
+func (noInputSource) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return touches
+}
+
+func (noInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	return 0, 0
+}
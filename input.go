@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// InputSource is a source of raw input states.
+//
+// An InputSource is sampled once per tick before widgets' HandleInput is called.
+// An InputSource can wrap another InputSource to record, replay or remap inputs.
+type InputSource interface {
+	CursorPosition() (int, int)
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	Wheel() (float64, float64)
+	IsKeyPressed(key ebiten.Key) bool
+	AppendInputChars(runes []rune) []rune
+	AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID
+	TouchPosition(id ebiten.TouchID) (int, int)
+}
+
+// imeInputSource is implemented by an InputSource whose typed characters come from Ebitengine's text input.
+// With Ebitengine's text input, input method editors (IMEs) are available.
+type imeInputSource interface {
+	isIMEAvailable() bool
+}
+
+// DefaultInputSource returns the InputSource that reads inputs from Ebitengine.
+func DefaultInputSource() InputSource {
+	return ebitenInputSource{}
+}
+
+type ebitenInputSource struct{}
+
+func (ebitenInputSource) CursorPosition() (int, int) {
+	return ebiten.CursorPosition()
+}
+
+func (ebitenInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+func (ebitenInputSource) Wheel() (float64, float64) {
+	return ebiten.Wheel()
+}
+
+func (ebitenInputSource) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (ebitenInputSource) AppendInputChars(runes []rune) []rune {
+	return ebiten.AppendInputChars(runes)
+}
+
+func (ebitenInputSource) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(touches)
+}
+
+func (ebitenInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	return ebiten.TouchPosition(id)
+}
+
+func (ebitenInputSource) isIMEAvailable() bool {
+	return true
+}
+
+// InputState is the input state of the current tick.
+//
+// InputState is updated from an InputSource once per tick. Widgets should read inputs from InputState
+// instead of Ebitengine's global state.
+type InputState struct {
+	cursorPosition image.Point
+	wheelX         float64
+	wheelY         float64
+
+	mouseButtonDurations     [ebiten.MouseButtonMax + 1]int
+	prevMouseButtonDurations [ebiten.MouseButtonMax + 1]int
+	keyDurations             [ebiten.KeyMax + 1]int
+	prevKeyDurations         [ebiten.KeyMax + 1]int
+
+	inputChars   []rune
+	imeAvailable bool
+
+	touchIDs           []ebiten.TouchID
+	touchPositions     map[ebiten.TouchID]image.Point
+	touchDurations     map[ebiten.TouchID]int
+	prevTouchDurations map[ebiten.TouchID]int
+}
+
+func (i *InputState) update(source InputSource) {
+	i.cursorPosition = image.Pt(source.CursorPosition())
+	i.wheelX, i.wheelY = source.Wheel()
+
+	i.prevMouseButtonDurations = i.mouseButtonDurations
+	for b := range i.mouseButtonDurations {
+		if source.IsMouseButtonPressed(ebiten.MouseButton(b)) {
+			i.mouseButtonDurations[b]++
+		} else {
+			i.mouseButtonDurations[b] = 0
+		}
+	}
+
+	i.prevKeyDurations = i.keyDurations
+	for k := range i.keyDurations {
+		if source.IsKeyPressed(ebiten.Key(k)) {
+			i.keyDurations[k]++
+		} else {
+			i.keyDurations[k] = 0
+		}
+	}
+
+	i.inputChars = slices.Delete(i.inputChars, 0, len(i.inputChars))
+	i.inputChars = source.AppendInputChars(i.inputChars)
+	if s, ok := source.(imeInputSource); ok {
+		i.imeAvailable = s.isIMEAvailable()
+	} else {
+		i.imeAvailable = false
+	}
+
+	if i.touchPositions == nil {
+		i.touchPositions = map[ebiten.TouchID]image.Point{}
+	}
+	if i.touchDurations == nil {
+		i.touchDurations = map[ebiten.TouchID]int{}
+	}
+	if i.prevTouchDurations == nil {
+		i.prevTouchDurations = map[ebiten.TouchID]int{}
+	}
+	clear(i.prevTouchDurations)
+	maps.Copy(i.prevTouchDurations, i.touchDurations)
+	clear(i.touchDurations)
+	clear(i.touchPositions)
+	i.touchIDs = slices.Delete(i.touchIDs, 0, len(i.touchIDs))
+	i.touchIDs = source.AppendTouchIDs(i.touchIDs)
+	for _, id := range i.touchIDs {
+		i.touchDurations[id] = i.prevTouchDurations[id] + 1
+		i.touchPositions[id] = image.Pt(source.TouchPosition(id))
+	}
+}
+
+// hasEvents reports whether any mouse button, key or touch is pressed or just released, the wheel is scrolled, or any character is input.
+func (i *InputState) hasEvents() bool {
+	if i.wheelX != 0 || i.wheelY != 0 {
+		return true
+	}
+	if len(i.inputChars) > 0 || len(i.touchIDs) > 0 || len(i.prevTouchDurations) > 0 {
+		return true
+	}
+	for b := range i.mouseButtonDurations {
+		if i.mouseButtonDurations[b] > 0 || i.prevMouseButtonDurations[b] > 0 {
+			return true
+		}
+	}
+	for k := range i.keyDurations {
+		if i.keyDurations[k] > 0 || i.prevKeyDurations[k] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CursorPosition returns the cursor position.
+func (i *InputState) CursorPosition() image.Point {
+	return i.cursorPosition
+}
+
+// IsMouseButtonPressed reports whether the mouse button is pressed.
+func (i *InputState) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return i.MouseButtonPressDuration(button) > 0
+}
+
+// IsMouseButtonJustPressed reports whether the mouse button is pressed in the current tick.
+func (i *InputState) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return i.MouseButtonPressDuration(button) == 1
+}
+
+// IsMouseButtonJustReleased reports whether the mouse button is released in the current tick.
+func (i *InputState) IsMouseButtonJustReleased(button ebiten.MouseButton) bool {
+	if button < 0 || button > ebiten.MouseButtonMax {
+		return false
+	}
+	return i.mouseButtonDurations[button] == 0 && i.prevMouseButtonDurations[button] > 0
+}
+
+// MouseButtonPressDuration returns how long the mouse button is pressed in ticks.
+func (i *InputState) MouseButtonPressDuration(button ebiten.MouseButton) int {
+	if button < 0 || button > ebiten.MouseButtonMax {
+		return 0
+	}
+	return i.mouseButtonDurations[button]
+}
+
+// Wheel returns the wheel movement in the current tick.
+func (i *InputState) Wheel() (float64, float64) {
+	return i.wheelX, i.wheelY
+}
+
+// IsKeyPressed reports whether the key is pressed.
+func (i *InputState) IsKeyPressed(key ebiten.Key) bool {
+	return i.KeyPressDuration(key) > 0
+}
+
+// IsKeyJustPressed reports whether the key is pressed in the current tick.
+func (i *InputState) IsKeyJustPressed(key ebiten.Key) bool {
+	return i.KeyPressDuration(key) == 1
+}
+
+// IsKeyJustReleased reports whether the key is released in the current tick.
+func (i *InputState) IsKeyJustReleased(key ebiten.Key) bool {
+	if key < 0 || key > ebiten.KeyMax {
+		return false
+	}
+	return i.keyDurations[key] == 0 && i.prevKeyDurations[key] > 0
+}
+
+// KeyPressDuration returns how long the key is pressed in ticks.
+func (i *InputState) KeyPressDuration(key ebiten.Key) int {
+	if key < 0 || key > ebiten.KeyMax {
+		return 0
+	}
+	return i.keyDurations[key]
+}
+
+// AppendInputChars appends the characters typed in the current tick to runes.
+func (i *InputState) AppendInputChars(runes []rune) []rune {
+	return append(runes, i.inputChars...)
+}
+
+// IsIMEAvailable reports whether typed characters are delivered through Ebitengine's text input, which supports input method editors (IMEs).
+//
+// IsIMEAvailable is true only for DefaultInputSource and the input of Embedded.
+// Otherwise, a widget accepting texts should read typed characters by AppendInputChars.
+func (i *InputState) IsIMEAvailable() bool {
+	return i.imeAvailable
+}
+
+// AppendTouchIDs appends the IDs of the current touches to touches.
+func (i *InputState) AppendTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	return append(touches, i.touchIDs...)
+}
+
+// AppendJustPressedTouchIDs appends the IDs of the touches started in the current tick to touches.
+func (i *InputState) AppendJustPressedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	for _, id := range i.touchIDs {
+		if i.touchDurations[id] == 1 {
+			touches = append(touches, id)
+		}
+	}
+	return touches
+}
+
+// AppendJustReleasedTouchIDs appends the IDs of the touches ended in the current tick to touches.
+func (i *InputState) AppendJustReleasedTouchIDs(touches []ebiten.TouchID) []ebiten.TouchID {
+	origLen := len(touches)
+	for id := range i.prevTouchDurations {
+		if _, ok := i.touchDurations[id]; ok {
+			continue
+		}
+		touches = append(touches, id)
+	}
+	slices.Sort(touches[origLen:])
+	return touches
+}
+
+// TouchPosition returns the position of the touch.
+func (i *InputState) TouchPosition(id ebiten.TouchID) image.Point {
+	return i.touchPositions[id]
+}
+
+// TouchPressDuration returns how long the touch continues in ticks.
+func (i *InputState) TouchPressDuration(id ebiten.TouchID) int {
+	return i.touchDurations[id]
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+	"math"
+)
+
+// Unbounded is the maximum size of Constraints without a limit.
+const Unbounded = math.MaxInt32
+
+// Constraints is the range of a size given to a widget to measure.
+type Constraints struct {
+	Min image.Point
+	Max image.Point
+}
+
+// UnboundedConstraints returns constraints without limits.
+func UnboundedConstraints() Constraints {
+	return Constraints{
+		Max: image.Pt(Unbounded, Unbounded),
+	}
+}
+
+// LooseConstraints returns constraints up to the given size.
+func LooseConstraints(maxWidth, maxHeight int) Constraints {
+	return Constraints{
+		Max: image.Pt(maxWidth, maxHeight),
+	}
+}
+
+// TightConstraints returns constraints only allowing the given size.
+func TightConstraints(width, height int) Constraints {
+	return Constraints{
+		Min: image.Pt(width, height),
+		Max: image.Pt(width, height),
+	}
+}
+
+// Constrain returns the size clamped by the constraints.
+func (c Constraints) Constrain(width, height int) (int, int) {
+	return min(max(width, c.Min.X), c.Max.X), min(max(height, c.Min.Y), c.Max.Y)
+}
+
+// HasBoundedWidth reports whether the maximum width is limited.
+func (c Constraints) HasBoundedWidth() bool {
+	return c.Max.X < Unbounded
+}
+
+// HasBoundedHeight reports whether the maximum height is limited.
+func (c Constraints) HasBoundedHeight() bool {
+	return c.Max.Y < Unbounded
+}
+
+// Measurement is the sizes a widget reports for constraints.
+type Measurement struct {
+	// Preferred is the size the widget would like to be.
+	Preferred image.Point
+
+	// Min is the smallest size the widget can be without losing its content.
+	Min image.Point
+
+	// Max is the largest size the widget can be. Unbounded means no limit.
+	Max image.Point
+}
+
+// Measurer is implemented by a widget that reports its sizes for constraints.
+//
+// For example, a text with wrapped lines reports the height for the maximum width of the constraints.
+// Measure must not depend on the widget's position or the widget's children appended in Layout.
+type Measurer interface {
+	Measure(context *Context, constraints Constraints) Measurement
+}
+
+type measurementCache struct {
+	frame   int64
+	entries map[Constraints]Measurement
+
+	// measuring is true while the widget is being measured, in order to avoid infinite recursion.
+	// For example, DefaultWidget's Size depends on its parent's Size.
+	measuring bool
+}
+
+// Measure measures the widget for the constraints.
+//
+// If the widget doesn't implement Measurer, the preferred size is Size, and the minimum and maximum sizes are the constraints.
+// All the sizes are clamped by the constraints.
+//
+// The results are cached until the next frame, or until RequestRelayout is called for the widget or its descendants.
+func Measure(context *Context, widget Widget, constraints Constraints) Measurement {
+	widgetState := widget.widgetState()
+	cache := &widgetState.measurementCache
+	if cache.frame != context.app.frame {
+		clear(cache.entries)
+		cache.frame = context.app.frame
+	}
+	if m, ok := cache.entries[constraints]; ok {
+		return m
+	}
+	if cache.measuring {
+		return Measurement{
+			Preferred: constraints.Min,
+			Min:       constraints.Min,
+			Max:       constraints.Max,
+		}
+	}
+	cache.measuring = true
+	defer func() {
+		cache.measuring = false
+	}()
+
+	var m Measurement
+	if measurer, ok := widget.(Measurer); ok {
+		m = measurer.Measure(context, constraints)
+	} else {
+		w, h := widget.Size(context)
+		m = Measurement{
+			Preferred: image.Pt(w, h),
+			Min:       constraints.Min,
+			Max:       constraints.Max,
+		}
+	}
+	m.Preferred.X, m.Preferred.Y = constraints.Constrain(m.Preferred.X, m.Preferred.Y)
+	m.Min.X, m.Min.Y = constraints.Constrain(m.Min.X, m.Min.Y)
+	m.Max.X, m.Max.Y = constraints.Constrain(m.Max.X, m.Max.Y)
+
+	if cache.entries == nil {
+		cache.entries = map[Constraints]Measurement{}
+	}
+	cache.entries[constraints] = m
+	return m
+}
+
+// RequestRelayout notifies that the widget's layout or sizes might be changed.
+//
+// Layout of the widget and its ancestors is called at the next layout pass, as a parent's layout might depend on its children's states.
+// Layout of the widget's descendants is also called, as a child's layout might depend on its parent's states.
+// The cached measurements of the widget and its ancestors are discarded.
+//
+// A widget should call RequestRelayout when a state affecting its Layout or its sizes is changed.
+// Show, Hide and a focus change also request relayout, while RequestRedraw doesn't.
+func RequestRelayout(widget Widget) {
+	widget.widgetState().relayoutRequested = true
+	for w := widget; w != nil; w = w.widgetState().parent {
+		widgetState := w.widgetState()
+		widgetState.needsLayout = true
+		clear(widgetState.measurementCache.entries)
+	}
+}
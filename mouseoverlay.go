@@ -7,7 +7,6 @@ import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type MouseOverlay struct {
@@ -30,34 +29,35 @@ func (m *MouseOverlay) SetOnUp(f func(mouseButton ebiten.MouseButton, cursorPosi
 }
 
 func (m *MouseOverlay) HandleInput(context *Context) HandleInputResult {
-	x, y := ebiten.CursorPosition()
-	m.setHovering(image.Pt(x, y).In(VisibleBounds(m)) && IsVisible(m))
+	input := context.Input()
+	cursorPosition := input.CursorPosition()
+	m.setHovering(cursorPosition.In(VisibleBounds(m)) && IsVisible(m))
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		if !image.Pt(ebiten.CursorPosition()).In(VisibleBounds(m)) {
+	if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		if !cursorPosition.In(VisibleBounds(m)) {
 			return HandleInputResult{}
 		}
 		if IsEnabled(m) {
-			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-				m.setPressing(true, ebiten.MouseButtonLeft)
+			if input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				m.setPressing(true, ebiten.MouseButtonLeft, cursorPosition)
 			}
-			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-				m.setPressing(true, ebiten.MouseButtonRight)
+			if input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+				m.setPressing(true, ebiten.MouseButtonRight, cursorPosition)
 			}
 		}
 		Focus(m)
 		return HandleInputByWidget(m)
 	}
 
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && m.pressingLeft ||
-		inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) && m.pressingRight {
+	if input.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && m.pressingLeft ||
+		input.IsMouseButtonJustReleased(ebiten.MouseButtonRight) && m.pressingRight {
 		if m.pressingLeft {
-			m.setPressing(false, ebiten.MouseButtonLeft)
+			m.setPressing(false, ebiten.MouseButtonLeft, cursorPosition)
 		}
 		if m.pressingRight {
-			m.setPressing(false, ebiten.MouseButtonRight)
+			m.setPressing(false, ebiten.MouseButtonRight, cursorPosition)
 		}
-		if !image.Pt(ebiten.CursorPosition()).In(VisibleBounds(m)) {
+		if !cursorPosition.In(VisibleBounds(m)) {
 			return HandleInputResult{}
 		}
 		if IsEnabled(m) {
@@ -65,11 +65,11 @@ func (m *MouseOverlay) HandleInput(context *Context) HandleInputResult {
 		}
 	}
 
-	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		m.setPressing(false, ebiten.MouseButtonLeft)
+	if !input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		m.setPressing(false, ebiten.MouseButtonLeft, cursorPosition)
 	}
-	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
-		m.setPressing(false, ebiten.MouseButtonRight)
+	if !input.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		m.setPressing(false, ebiten.MouseButtonRight, cursorPosition)
 	}
 
 	return HandleInputResult{}
@@ -82,7 +82,7 @@ func (m *MouseOverlay) Update(context *Context) error {
 	return nil
 }
 
-func (m *MouseOverlay) setPressing(pressing bool, mouseButton ebiten.MouseButton) {
+func (m *MouseOverlay) setPressing(pressing bool, mouseButton ebiten.MouseButton, cursorPosition image.Point) {
 	switch mouseButton {
 	case ebiten.MouseButtonLeft:
 		if m.pressingLeft == pressing {
@@ -95,14 +95,14 @@ func (m *MouseOverlay) setPressing(pressing bool, mouseButton ebiten.MouseButton
 	}
 
 	if IsEnabled(m) {
-		if p := image.Pt(ebiten.CursorPosition()); p.In(VisibleBounds(Parent(m))) {
+		if cursorPosition.In(VisibleBounds(Parent(m))) {
 			if pressing {
 				if m.onDown != nil {
-					m.onDown(mouseButton, p)
+					m.onDown(mouseButton, cursorPosition)
 				}
 			} else {
 				if m.onUp != nil {
-					m.onUp(mouseButton, p)
+					m.onUp(mouseButton, cursorPosition)
 				}
 			}
 		}
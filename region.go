@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+	"slices"
+)
+
+// maxRegionCount is the maximum number of rectangles in a regionSet.
+// Each rectangle costs a traversal of the widgets to draw, so too many rectangles are slower than a larger rectangle.
+const maxRegionCount = 8
+
+// regionSet is a set of non-overlapping rectangles.
+//
+// Rectangles are merged when they overlap, or when the merged rectangle is not much larger than the two rectangles.
+type regionSet struct {
+	rects []image.Rectangle
+}
+
+func area(r image.Rectangle) int {
+	return r.Dx() * r.Dy()
+}
+
+// shouldMergeRegions reports whether drawing the union of r0 and r1 at once is cheaper than drawing them separately.
+func shouldMergeRegions(r0, r1 image.Rectangle) bool {
+	if r0.Overlaps(r1) {
+		return true
+	}
+	// Allow the extra area up to the half of the two rectangles.
+	return 2*area(r0.Union(r1)) <= 3*(area(r0)+area(r1))
+}
+
+func (r *regionSet) add(region image.Rectangle) {
+	if region.Empty() {
+		return
+	}
+
+	// Merging might make the new rectangle overlap with another rectangle, so repeat until nothing is merged.
+	for {
+		idx := slices.IndexFunc(r.rects, func(rect image.Rectangle) bool {
+			return shouldMergeRegions(rect, region)
+		})
+		if idx < 0 {
+			break
+		}
+		region = region.Union(r.rects[idx])
+		r.rects = slices.Delete(r.rects, idx, idx+1)
+	}
+	r.rects = append(r.rects, region)
+
+	for len(r.rects) > maxRegionCount {
+		r.mergeCheapestPair()
+	}
+}
+
+// mergeCheapestPair merges the two rectangles whose union has the least extra area.
+func (r *regionSet) mergeCheapestPair() {
+	i0, i1 := -1, -1
+	var minCost int
+	for i := range r.rects {
+		for j := i + 1; j < len(r.rects); j++ {
+			cost := area(r.rects[i].Union(r.rects[j])) - area(r.rects[i]) - area(r.rects[j])
+			if i0 < 0 || cost < minCost {
+				i0, i1 = i, j
+				minCost = cost
+			}
+		}
+	}
+	if i0 < 0 {
+		return
+	}
+	region := r.rects[i0].Union(r.rects[i1])
+	r.rects = slices.Delete(r.rects, i1, i1+1)
+	r.rects = slices.Delete(r.rects, i0, i0+1)
+	// Add the union again, as it might overlap with other rectangles.
+	r.add(region)
+}
+
+func (r *regionSet) empty() bool {
+	return len(r.rects) == 0
+}
+
+func (r *regionSet) reset() {
+	r.rects = slices.Delete(r.rects, 0, len(r.rects))
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2025 Hajime Hoshi
+
+package guigui
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+func TestRegionSet(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rects []image.Rectangle
+		want  []image.Rectangle
+	}{
+		{
+			name:  "distant",
+			rects: []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(300, 200, 310, 210)},
+			want:  []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(300, 200, 310, 210)},
+		},
+		{
+			name:  "overlapping",
+			rects: []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(5, 5, 100, 100)},
+			want:  []image.Rectangle{image.Rect(0, 0, 100, 100)},
+		},
+		{
+			name:  "adjacent",
+			rects: []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(10, 0, 20, 10)},
+			want:  []image.Rectangle{image.Rect(0, 0, 20, 10)},
+		},
+		{
+			name:  "chained",
+			rects: []image.Rectangle{image.Rect(0, 0, 10, 10), image.Rect(100, 0, 110, 10), image.Rect(5, 0, 105, 10)},
+			want:  []image.Rectangle{image.Rect(0, 0, 110, 10)},
+		},
+		{
+			name:  "empty",
+			rects: []image.Rectangle{{}, image.Rect(0, 0, 10, 10)},
+			want:  []image.Rectangle{image.Rect(0, 0, 10, 10)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var r regionSet
+			for _, rect := range tc.rects {
+				r.add(rect)
+			}
+			if !slices.Equal(r.rects, tc.want) {
+				t.Errorf("got: %v, want: %v", r.rects, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegionSetMaxCount(t *testing.T) {
+	var r regionSet
+	var all image.Rectangle
+	for i := range 4 * maxRegionCount {
+		rect := image.Rect(0, 0, 10, 10).Add(image.Pt(i*37%200*10, i*53%150*10))
+		r.add(rect)
+		all = all.Union(rect)
+	}
+	if got := len(r.rects); got > maxRegionCount {
+		t.Errorf("len(rects): got: %d, want: <= %d", got, maxRegionCount)
+	}
+	var union image.Rectangle
+	for i, r0 := range r.rects {
+		union = union.Union(r0)
+		for _, r1 := range r.rects[i+1:] {
+			if r0.Overlaps(r1) {
+				t.Errorf("%v and %v overlap", r0, r1)
+			}
+		}
+	}
+	if union != all {
+		t.Errorf("union: got: %v, want: %v", union, all)
+	}
+}
@@ -4,6 +4,8 @@
 package guigui
 
 import (
+	"slices"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -15,6 +17,7 @@ type Widget interface {
 	Draw(context *Context, dst *ebiten.Image)
 	IsPopup() bool
 	Size(context *Context) (int, int)
+	OnFocusChanged(context *Context, event FocusChangedEvent)
 
 	widgetState() *widgetState
 }
@@ -40,10 +43,26 @@ func (r *HandleInputResult) ShouldRaise() bool {
 	return r.widget != nil || r.aborted
 }
 
+// FocusChangedEvent represents a focus change for a widget.
+type FocusChangedEvent struct {
+	// Focused reports whether the widget itself is focused after the change.
+	Focused bool
+
+	// FocusWithin reports whether the widget or one of its descendants is focused after the change.
+	FocusWithin bool
+}
+
 func Parent(widget Widget) Widget {
 	return widget.widgetState().parent
 }
 
+// ChildWidgets returns the child widgets of the given widget in the rendering order.
+//
+// The result reflects the tree built at the last layout pass.
+func ChildWidgets(widget Widget) []Widget {
+	return slices.Clone(widget.widgetState().children)
+}
+
 type RootWidget struct {
 	DefaultWidget
 }
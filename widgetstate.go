@@ -5,6 +5,7 @@ package guigui
 
 import (
 	"image"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -40,10 +41,10 @@ func (w *widgetsAndBounds) equals(currentWidgets []Widget) bool {
 	return true
 }
 
-func (w *widgetsAndBounds) redrawIfAboveParentZ() {
+func (w *widgetsAndBounds) redrawIfAboveParentZ(app *app) {
 	for widget := range w.bounds {
 		if isAboveParentZ(widget) {
-			RequestRedraw(widget)
+			app.requestRedrawWidget(widget)
 		}
 	}
 }
@@ -51,6 +52,10 @@ func (w *widgetsAndBounds) redrawIfAboveParentZ() {
 type widgetState struct {
 	root bool
 
+	// app is the app that the widget belongs to.
+	// app is set when the widget is added to a tree, and is nil until then.
+	app *app
+
 	position image.Point
 
 	parent   Widget
@@ -61,6 +66,36 @@ type widgetState struct {
 	disabled     bool
 	transparency float64
 
+	focusable bool
+	focusTrap bool
+	tabIndex  int
+
+	// focusChangedEvent is the last event dispatched to OnFocusChanged.
+	focusChangedEvent FocusChangedEvent
+
+	measurementCache measurementCache
+
+	// needsLayout is true when Layout must be called at the next layout pass.
+	needsLayout bool
+
+	// relayoutRequested is true when RequestRelayout is called for the widget itself.
+	// Layout of the descendants must also be called at the next layout pass.
+	relayoutRequested bool
+
+	// laidOut is true when Layout has been called at least once.
+	laidOut bool
+
+	// layoutBounds is the bounds at the last Layout call.
+	layoutBounds image.Rectangle
+
+	// z is the z value calculated at the last layout pass.
+	z int
+
+	// treeIndex is the index in the pre-order traversal of the tree at the last layout pass.
+	// The descendants' indices are in [treeIndex+1, treeEnd).
+	treeIndex int
+	treeEnd   int
+
 	offscreen *ebiten.Image
 }
 
@@ -73,19 +108,45 @@ func SetPosition(widget Widget, position image.Point) {
 	// Rerendering happens at a.addInvalidatedRegions if necessary.
 }
 
+// Bounds returns the widget's bounds.
+//
+// A widget not in an app's tree yet, e.g. a widget before its first layout, is measured with a detached context.
+// The detached context has the default settings, like the device scale 1, regardless of the apps.
 func Bounds(widget Widget) image.Rectangle {
 	widgetState := widget.widgetState()
-	width, height := widget.Size(&theApp.context)
+	context := detachedContext()
+	if widgetState.app != nil {
+		context = &widgetState.app.context
+	}
+	width, height := widget.Size(context)
 	return image.Rectangle{
 		Min: widgetState.position,
 		Max: widgetState.position.Add(image.Point{width, height}),
 	}
 }
 
+var (
+	theDetachedApp     *app
+	theDetachedAppOnce sync.Once
+)
+
+// detachedContext returns the context to measure a widget that doesn't belong to any app.
+func detachedContext() *Context {
+	theDetachedAppOnce.Do(func() {
+		theDetachedApp = newApp(&DefaultWidget{}, noInputSource{})
+		theDetachedApp.context.deviceScale = 1
+	})
+	return &theDetachedApp.context
+}
+
 func VisibleBounds(widget Widget) image.Rectangle {
-	parent := widget.widgetState().parent
+	widgetState := widget.widgetState()
+	parent := widgetState.parent
 	if parent == nil {
-		return theApp.bounds()
+		if widgetState.app == nil {
+			return image.Rectangle{}
+		}
+		return widgetState.app.bounds()
 	}
 	if isAboveParentZ(widget) {
 		return Bounds(widget)
@@ -107,6 +168,8 @@ func Show(widget Widget) {
 	}
 	widgetState.hidden = false
 	RequestRedraw(widget)
+	// The parent's layout might skip a hidden widget.
+	RequestRelayout(widget)
 }
 
 func Hide(widget Widget) {
@@ -117,6 +180,7 @@ func Hide(widget Widget) {
 	widgetState.hidden = true
 	Blur(widget)
 	RequestRedraw(widget)
+	RequestRelayout(widget)
 }
 
 func IsVisible(widget Widget) bool {
@@ -172,20 +236,7 @@ func Focus(widget Widget) {
 	if !widgetState.isInTree() {
 		return
 	}
-	if theApp.focusedWidget == widget {
-		return
-	}
-
-	var oldWidget Widget
-	if theApp.focusedWidget != nil {
-		oldWidget = theApp.focusedWidget
-	}
-
-	theApp.focusedWidget = widget
-	RequestRedraw(theApp.focusedWidget)
-	if oldWidget != nil {
-		RequestRedraw(oldWidget)
-	}
+	widgetState.app.setFocusedWidget(widget)
 }
 
 func Blur(widget Widget) {
@@ -193,25 +244,34 @@ func Blur(widget Widget) {
 	if !widgetState.isInTree() {
 		return
 	}
-	if theApp.focusedWidget != widget {
+	a := widgetState.app
+	if a.focusedWidget != widget {
 		return
 	}
-	theApp.focusedWidget = nil
-	RequestRedraw(widget)
+	a.setFocusedWidget(nil)
 }
 
 func IsFocused(widget Widget) bool {
 	widgetState := widget.widgetState()
-	return widgetState.isInTree() && theApp.focusedWidget == widget && widgetState.isVisible()
+	return widgetState.isInTree() && widgetState.app.focusedWidget == widget && widgetState.isVisible()
 }
 
+// HasFocusedChildWidget reports whether the widget or one of its descendants is focused.
 func HasFocusedChildWidget(widget Widget) bool {
 	widgetState := widget.widgetState()
-	if IsFocused(widget) {
-		return true
+	if !widgetState.isInTree() {
+		return false
+	}
+	focused := widgetState.app.focusedWidget
+	if focused == nil || !IsVisible(focused) {
+		return false
 	}
-	for _, child := range widgetState.children {
-		if HasFocusedChildWidget(child) {
+	return isAncestorOrSelf(widget, focused)
+}
+
+func isAncestorOrSelf(ancestor Widget, widget Widget) bool {
+	for w := widget; w != nil; w = w.widgetState().parent {
+		if w == ancestor {
 			return true
 		}
 	}
@@ -241,8 +301,16 @@ func SetOpacity(widget Widget, opacity float64) {
 	RequestRedraw(widget)
 }
 
+// RequestRedraw requests to redraw the widget's region.
+//
+// RequestRedraw doesn't request relayout. Call RequestRelayout as well when the widget's sizes or children might be changed.
 func RequestRedraw(widget Widget) {
-	theApp.requestRedrawWidget(widget)
+	a := widget.widgetState().app
+	if a == nil {
+		// The widget is not in a tree yet. The region is redrawn when the widget is added to the tree.
+		return
+	}
+	a.requestRedrawWidget(widget)
 }
 
 func (w *widgetState) ensureOffscreen(bounds image.Rectangle) *ebiten.Image {
@@ -258,26 +326,9 @@ func (w *widgetState) ensureOffscreen(bounds image.Rectangle) *ebiten.Image {
 	return w.offscreen.SubImage(bounds).(*ebiten.Image)
 }
 
-func traverseWidget(widget Widget, f func(widget Widget)) {
-	f(widget)
-	for _, child := range widget.widgetState().children {
-		traverseWidget(child, f)
-	}
-}
-
+// z returns the z value of the widget calculated at the last layout pass.
 func z(widget Widget) int {
-	parent := widget.widgetState().parent
-	if parent == nil {
-		if widget.IsPopup() {
-			return 1
-		}
-		return 0
-	}
-	z := z(parent)
-	if widget.IsPopup() {
-		z++
-	}
-	return z
+	return widget.widgetState().z
 }
 
 func isAboveParentZ(widget Widget) bool {